@@ -0,0 +1,316 @@
+package gas
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errBindTarget = errors.New("bind target must be a pointer to struct")
+
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeaderSliceType = reflect.TypeOf([]*multipart.FileHeader(nil))
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind decodes request data into v, the decoder is chosen by Content-Type:
+//
+//	application/json                   json.Unmarshal, using `json` tag
+//	application/xml, text/xml          xml.Unmarshal, using `xml` tag
+//	application/x-www-form-urlencoded  post args, using `form` tag
+//	multipart/form-data                form values and files, using `form` tag
+//
+// Requests without body bind query string by BindQuery.
+// Path parameters are bound at last by BindPath if v is a pointer to struct.
+//
+// Decoding errors are returned as HTTPError with status 400,
+// unsupported Content-Type is returned as HTTPError with status 415.
+//
+// Ex:
+//
+//	type UserForm struct {
+//		ID     int                   `path:"id"`
+//		Name   string                `json:"name" form:"name"`
+//		Tags   []string              `json:"tags" form:"tag"`
+//		Avatar *multipart.FileHeader `form:"avatar"`
+//	}
+//
+//	func UpdateUser(c *gas.Context) error {
+//		f := &UserForm{}
+//		if err := c.Bind(f); err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (ctx *Context) Bind(v interface{}) error {
+	if len(ctx.PostBody()) == 0 {
+		if err := ctx.BindQuery(v); err != nil && err != errBindTarget {
+			return err
+		}
+	} else if err := ctx.bindBody(v); err != nil {
+		return err
+	}
+
+	if err := ctx.BindPath(v); err != nil && err != errBindTarget {
+		return err
+	}
+
+	return nil
+}
+
+func (ctx *Context) bindBody(v interface{}) error {
+	ct := string(ctx.Request.Header.ContentType())
+	if i := strings.IndexByte(ct, ';'); i != -1 {
+		ct = ct[:i]
+	}
+	ct = strings.TrimSpace(strings.ToLower(ct))
+
+	switch {
+	case ct == ApplicationJSON || strings.HasSuffix(ct, "+json"):
+		if err := json.Unmarshal(ctx.PostBody(), v); err != nil {
+			return NewHTTPError(http.StatusBadRequest, "invalid JSON body").SetInternal(err)
+		}
+	case ct == ApplicationXML || ct == TextXML || strings.HasSuffix(ct, "+xml"):
+		if err := xml.Unmarshal(ctx.PostBody(), v); err != nil {
+			return NewHTTPError(http.StatusBadRequest, "invalid XML body").SetInternal(err)
+		}
+	case ct == ApplicationForm:
+		args := ctx.PostArgs()
+		return bindBadRequest(bindValues(v, "form", func(name string) []string {
+			return bytesSliceToStrings(args.PeekMulti(name))
+		}, nil))
+	case ct == MultipartForm:
+		form, err := ctx.MultipartForm()
+		if err != nil {
+			return NewHTTPError(http.StatusBadRequest, "invalid multipart form").SetInternal(err)
+		}
+
+		return bindBadRequest(bindValues(v, "form", func(name string) []string {
+			return form.Value[name]
+		}, func(name string) []*multipart.FileHeader {
+			return form.File[name]
+		}))
+	default:
+		return NewHTTPError(http.StatusUnsupportedMediaType)
+	}
+
+	return nil
+}
+
+// BindQuery decodes query string into v which must be a pointer to struct,
+// fields are matched by `query` tag, `form` tag or field name.
+func (ctx *Context) BindQuery(v interface{}) error {
+	args := ctx.QueryArgs()
+
+	return bindBadRequest(bindValues(v, "query", func(name string) []string {
+		return bytesSliceToStrings(args.PeekMulti(name))
+	}, nil))
+}
+
+// BindPath decodes path parameters into v which must be a pointer to struct,
+// fields are matched by `path` tag or field name.
+//
+// Ex:
+//
+//	// r.Get("/users/:id", ShowUser)
+//	var p struct {
+//		ID int64 `path:"id"`
+//	}
+//	c.BindPath(&p)
+func (ctx *Context) BindPath(v interface{}) error {
+	return bindBadRequest(bindValues(v, "path", func(name string) []string {
+		if s, ok := ctx.UserValue(name).(string); ok {
+			return []string{s}
+		}
+
+		return nil
+	}, nil))
+}
+
+func bindBadRequest(err error) error {
+	if err == nil || err == errBindTarget {
+		return err
+	}
+
+	return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+}
+
+func bytesSliceToStrings(bs [][]byte) []string {
+	if len(bs) == 0 {
+		return nil
+	}
+
+	res := make([]string, len(bs))
+	for i, b := range bs {
+		res[i] = string(b)
+	}
+
+	return res
+}
+
+// bindValues sets struct fields of ptr by values and files found with field names,
+// the name of field is read from tag, `query` tag falls back to `form` tag.
+func bindValues(ptr interface{}, tag string, values func(string) []string, files func(string) []*multipart.FileHeader) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errBindTarget
+	}
+
+	return bindStruct(rv.Elem(), tag, values, files)
+}
+
+func bindStruct(sv reflect.Value, tag string, values func(string) []string, files func(string) []*multipart.FileHeader) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		fv := sv.Field(i)
+
+		name := fieldName(sf, tag)
+		if name == "-" {
+			continue
+		}
+
+		// embedded struct shares the same namespace
+		if sf.Anonymous && fv.Kind() == reflect.Struct && name == sf.Name {
+			if err := bindStruct(fv, tag, values, files); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !fv.CanSet() {
+			continue
+		}
+
+		if sf.Type == fileHeaderType || sf.Type == fileHeaderSliceType {
+			if files == nil {
+				continue
+			}
+			if fhs := files(name); len(fhs) != 0 {
+				if sf.Type == fileHeaderType {
+					fv.Set(reflect.ValueOf(fhs[0]))
+				} else {
+					fv.Set(reflect.ValueOf(fhs))
+				}
+			}
+			continue
+		}
+
+		vals := values(name)
+		if len(vals) == 0 {
+			continue
+		}
+
+		if err := setField(fv, vals); err != nil {
+			return errors.New(name + ": " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func fieldName(sf reflect.StructField, tag string) string {
+	t := sf.Tag.Get(tag)
+	if t == "" && tag == "query" {
+		t = sf.Tag.Get("form")
+	}
+
+	if i := strings.IndexByte(t, ','); i != -1 {
+		t = t[:i]
+	}
+
+	if t == "" {
+		return sf.Name
+	}
+
+	return t
+}
+
+// setField sets vals to field, only the first one is used if field is not a slice.
+func setField(fv reflect.Value, vals []string) error {
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+		s := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+		for i, v := range vals {
+			if err := setValue(s.Index(i), v); err != nil {
+				return err
+			}
+		}
+		fv.Set(s)
+
+		return nil
+	}
+
+	return setValue(fv, vals[0])
+}
+
+func setValue(fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+
+		return setValue(fv.Elem(), s)
+	}
+
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		if s == "" || s == "on" {
+			fv.SetBool(s == "on")
+			return nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if fv.Type() == durationType {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			fv.SetInt(int64(d))
+
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	case reflect.Slice:
+		// []byte
+		fv.SetBytes([]byte(s))
+	default:
+		return errors.New("unsupported type " + fv.Type().String())
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package gas
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type bindUser struct {
+	ID       int64     `path:"id" json:"-" xml:"-"`
+	Name     string    `json:"name" xml:"name" form:"name"`
+	Age      int       `json:"age" xml:"age" form:"age"`
+	Tags     []string  `json:"tags" xml:"tag" form:"tag"`
+	Admin    bool      `form:"admin" query:"is_admin"`
+	Birthday time.Time `form:"birthday"`
+	Score    *float64  `form:"score"`
+	Ignored  string    `form:"-"`
+
+	bindPage
+
+	Avatar *multipart.FileHeader   `form:"avatar"`
+	Photos []*multipart.FileHeader `form:"photo"`
+}
+
+type bindPage struct {
+	Page int `query:"page" form:"page"`
+}
+
+func TestContext_BindJSON(t *testing.T) {
+	as := assert.New(t)
+
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	var u *bindUser
+	g.Router.Put("/users/:id", func(c *Context) error {
+		u = &bindUser{}
+		if err := c.Bind(u); err != nil {
+			return err
+		}
+
+		return c.STRING(http.StatusOK, "OK")
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.PUT("/users/10").WithJSON(H{"name": "John", "age": 32, "tags": []string{"a", "b"}}).
+		Expect().Status(http.StatusOK)
+
+	as.Equal(int64(10), u.ID)
+	as.Equal("John", u.Name)
+	as.Equal(32, u.Age)
+	as.Equal([]string{"a", "b"}, u.Tags)
+
+	e.PUT("/users/10").WithHeader("Content-Type", ApplicationJSON).WithBytes([]byte("{name")).
+		Expect().Status(http.StatusBadRequest)
+
+	e.PUT("/users/10").WithHeader("Content-Type", "text/csv").WithBytes([]byte("a,b")).
+		Expect().Status(http.StatusUnsupportedMediaType)
+
+	// path parameter is not a number
+	e.PUT("/users/abc").WithJSON(H{"name": "John"}).
+		Expect().Status(http.StatusBadRequest)
+}
+
+func TestContext_BindXML(t *testing.T) {
+	as := assert.New(t)
+
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	u := &bindUser{}
+	g.Router.Post("/users", func(c *Context) error {
+		return c.Bind(u)
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.POST("/users").WithHeader("Content-Type", TextXMLCharsetUTF8).
+		WithBytes([]byte("<user><name>John</name><age>32</age><tag>a</tag><tag>b</tag></user>")).
+		Expect().Status(http.StatusOK)
+
+	as.Equal("John", u.Name)
+	as.Equal(32, u.Age)
+	as.Equal([]string{"a", "b"}, u.Tags)
+}
+
+func TestContext_BindForm(t *testing.T) {
+	as := assert.New(t)
+
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	u := &bindUser{}
+	g.Router.Post("/users", func(c *Context) error {
+		return c.Bind(u)
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.POST("/users").
+		WithFormField("name", "John").
+		WithFormField("age", "32").
+		WithFormField("tag", "a").
+		WithFormField("tag", "b").
+		WithFormField("admin", "on").
+		WithFormField("birthday", "2000-01-02T03:04:05Z").
+		WithFormField("score", "9.5").
+		WithFormField("page", "3").
+		WithFormField("Ignored", "x").
+		Expect().Status(http.StatusOK)
+
+	as.Equal("John", u.Name)
+	as.Equal(32, u.Age)
+	as.Equal([]string{"a", "b"}, u.Tags)
+	as.True(u.Admin)
+	as.Equal(time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC), u.Birthday)
+	as.Equal(9.5, *u.Score)
+	as.Equal(3, u.Page)
+	as.Equal("", u.Ignored)
+
+	e.POST("/users").WithFormField("age", "old").
+		Expect().Status(http.StatusBadRequest)
+}
+
+func TestContext_BindMultipart(t *testing.T) {
+	as := assert.New(t)
+
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	u := &bindUser{}
+	var avatar, photo2 []byte
+	g.Router.Post("/users", func(c *Context) error {
+		if err := c.Bind(u); err != nil {
+			return err
+		}
+
+		f, _ := u.Avatar.Open()
+		avatar, _ = ioutil.ReadAll(f)
+		f.Close()
+
+		f, _ = u.Photos[1].Open()
+		photo2, _ = ioutil.ReadAll(f)
+		f.Close()
+
+		return nil
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.POST("/users").WithMultipart().
+		WithFormField("name", "John").
+		WithFileBytes("avatar", "avatar.png", []byte("avatar data")).
+		WithFileBytes("photo", "1.png", []byte("photo 1")).
+		WithFileBytes("photo", "2.png", []byte("photo 2")).
+		Expect().Status(http.StatusOK)
+
+	as.Equal("John", u.Name)
+	as.Equal("avatar.png", u.Avatar.Filename)
+	as.Equal("avatar data", string(avatar))
+	as.Len(u.Photos, 2)
+	as.Equal("photo 2", string(photo2))
+}
+
+func TestContext_BindQuery(t *testing.T) {
+	as := assert.New(t)
+
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	u := &bindUser{}
+	g.Router.Get("/users/:id", func(c *Context) error {
+		return c.Bind(u)
+	})
+	g.Router.Get("/query", func(c *Context) error {
+		var m map[string]string
+		return c.BindQuery(&m)
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/users/5").
+		WithQuery("name", "John").
+		WithQuery("is_admin", "true").
+		WithQuery("page", "2").
+		Expect().Status(http.StatusOK)
+
+	as.Equal(int64(5), u.ID)
+	as.Equal("John", u.Name)
+	as.True(u.Admin)
+	as.Equal(2, u.Page)
+
+	// only pointer to struct is supported
+	e.GET("/query").Expect().Status(http.StatusInternalServerError)
+}
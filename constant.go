@@ -14,10 +14,14 @@ const (
 	ApplicationForm                  = "application/x-www-form-urlencoded"
 	ApplicationProtobuf              = "application/protobuf"
 	ApplicationMsgpack               = "application/msgpack"
+	ApplicationYAML                  = "application/x-yaml"
+	ApplicationYAMLCharsetUTF8       = ApplicationYAML + "; " + CharsetUTF8
 	TextHTML                         = "text/html"
 	TextHTMLCharsetUTF8              = TextHTML + "; " + CharsetUTF8
 	TextPlain                        = "text/plain"
 	TextPlainCharsetUTF8             = TextPlain + "; " + CharsetUTF8
+	TextXML                          = "text/xml"
+	TextXMLCharsetUTF8               = TextXML + "; " + CharsetUTF8
 	MultipartForm                    = "multipart/form-data"
 
 	//---------
@@ -1,9 +1,9 @@
 package gas
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
-	"html/template"
 	"time"
 
 	"github.com/go-gas/gas/model"
@@ -36,6 +36,11 @@ type Context struct {
 	isUseSession   bool
 	sessionManager *sessions.SessionManager
 	cookieHandler  sessions.HTTPCookieHandlerInterface
+
+	// values of Set and the context of StdContext
+	store  *store
+	stdCtx context.Context
+	cancel context.CancelFunc
 }
 
 type CookieSettings struct {
@@ -82,13 +87,19 @@ func (ctx *Context) reset(fctx *fasthttp.RequestCtx, g *Engine) {
 
 	ctx.isUseSession = false
 	ctx.cookieHandler = nil
+
+	// the store may still be referenced by StdContext, so it's dropped instead of cleared
+	ctx.store = nil
+	ctx.stdCtx = nil
+	ctx.cancel = nil
 }
 
 // func (ctx *Context) Next()  {
 //     ctx.handlerFunc(ctx)
 // }
 
-// Get parameter from post or get value
+// Get parameter from post or get value, or path parameter,
+// empty string is returned if it does not exist.
 func (ctx *Context) GetParam(name string) string {
 	//if ctx.Req.PostForm == nil || ctx.Req.Form == nil {
 	//	ctx.Req.ParseForm()
@@ -102,7 +113,9 @@ func (ctx *Context) GetParam(name string) string {
 		return string(fv)
 	}
 
-	return ctx.UserValue(name).(string)
+	v, _ := ctx.PathParam(name)
+
+	return v
 }
 
 //func (ctx *Context) GetFormValue(name string) string {
@@ -121,32 +134,45 @@ func (ctx *Context) GetParam(name string) string {
 //     }
 // }
 
-// Render function combined data and template to show
+// Render function combined data and template to show,
+// templates are parsed in order by engine's View and cached,
+// the "gas" template is executed if defined, otherwise the first one.
 func (ctx *Context) Render(data interface{}, tplPath ...string) error {
-	if len(tplPath) == 0 {
-		return errors.New("File path can not be empty")
-	}
-
-	ctx.SetContentType(TextHTMLCharsetUTF8)
+	return ctx.render(data, tplPath)
+}
 
-	// tpls := strings.Join(tplPath, ", ")
-	tmpl := template.New("gas")
+// View renders template name in views directory with layout,
+// the default layout in config is used if layout is not given,
+// empty layout means rendering the template only.
+//
+// Ex:
+//
+//	ctx.View("users/index", gas.H{"Users": users})
+//	ctx.View("users/print", data, "layouts/print")
+func (ctx *Context) View(name string, data interface{}, layout ...string) error {
+	l := ctx.gas.View.Layout
+	if len(layout) != 0 {
+		l = layout[0]
+	}
 
-	for _, tpath := range tplPath {
-		tmpl = template.Must((tmpl.ParseFiles(tpath)))
+	if l == "" {
+		return ctx.render(data, []string{name})
 	}
 
-	err := tmpl.Execute(ctx, data)
+	return ctx.render(data, []string{l, name})
+}
 
-	return err
-	// if err != nil {
-	//     // println(err)
-	//     // panic(err)
+// render executes templates to a buffer first, so nothing is written if it fails
+func (ctx *Context) render(data interface{}, names []string) error {
+	var buf bytes.Buffer
+	if err := ctx.gas.View.Render(&buf, data, names...); err != nil {
+		return err
+	}
 
-	//     return err
-	// }
+	ctx.SetContentType(TextHTMLCharsetUTF8)
+	ctx.Write(buf.Bytes())
 
-	// return nil
+	return nil
 }
 
 // Set the response data-type to html
@@ -161,16 +187,7 @@ func (ctx *Context) HTML(code int, html string) error {
 
 // Set the response data-type to plain text
 func (ctx *Context) STRING(status int, data string) error {
-
-	//ctx.RespWriter.Header().Set(ContentType, TextPlainCharsetUTF8)
-	//ctx.RespWriter.WriteHeader(status)
-	//_, err := ctx.RespWriter.Write([]byte(data))
-
-	if ctx.IsGet() {
-		ctx.SetContentType(TextPlainCharsetUTF8)
-	} else {
-		ctx.SetContentType(ApplicationForm)
-	}
+	ctx.SetContentType(TextPlainCharsetUTF8)
 	ctx.SetStatusCode(status)
 	_, err := ctx.WriteString(data)
 	return err
@@ -193,6 +210,11 @@ func (ctx *Context) JSON(status int, data interface{}) error {
 	return errr
 }
 
+// acceptsJSON reports whether client prefers JSON to HTML by q-values in Accept header
+func (ctx *Context) acceptsJSON() bool {
+	return ctx.NegotiateFormat(TextHTML, ApplicationJSON) == ApplicationJSON
+}
+
 func (ctx *Context) SetHeader(key, value string) {
 	ctx.Response.Header.Set(key, value)
 }
@@ -1,6 +1,9 @@
 package gas
 
-// ControllerInterface defind controller interface
+import "reflect"
+
+// ControllerInterface defind controller interface,
+// a controller may implement Preparer, Finisher and MiddlewareDeclarer.
 type ControllerInterface interface {
 }
 
@@ -8,3 +11,107 @@ type ControllerInterface interface {
 type Controller struct {
 	ControllerInterface
 }
+
+type (
+	// Preparer is called before the action of a controller,
+	// the action is not called if it returns an error.
+	Preparer interface {
+		Prepare(*Context) error
+	}
+
+	// Finisher is called after the action of a controller, even if the action returned an error or panicked
+	Finisher interface {
+		Finish(*Context)
+	}
+
+	// MiddlewareDeclarer declares middlewares run before all actions of a controller
+	MiddlewareDeclarer interface {
+		Middlewares() []interface{}
+	}
+
+	// ControllerFactory creates a controller for each request,
+	// it can be registered by REST and Resource instead of a controller.
+	// Use it if the controller has maps, slices or pointers modified in requests,
+	// since a registered controller is only copied shallowly.
+	ControllerFactory func() ControllerInterface
+)
+
+// controllerType creates a controller for each request, so that fields set in one request
+// do not leak into others. Pointers to struct are copied from the registered controller,
+// so dependencies set in it are kept. The copy is shallow, maps, slices and pointers
+// in fields are shared by all requests, use ControllerFactory if they are modified.
+type controllerType struct {
+	proto   reflect.Value
+	factory ControllerFactory
+}
+
+func newControllerType(c ControllerInterface) *controllerType {
+	switch f := c.(type) {
+	case ControllerFactory:
+		return &controllerType{proto: reflect.ValueOf(f()), factory: f}
+	case func() ControllerInterface:
+		return &controllerType{proto: reflect.ValueOf(f()), factory: f}
+	}
+
+	return &controllerType{proto: reflect.ValueOf(c)}
+}
+
+// sample returns the registered controller or the one created by factory for registration
+func (ct *controllerType) sample() ControllerInterface {
+	return ct.proto.Interface()
+}
+
+// middlewares returns middlewares declared by the controller
+func (ct *controllerType) middlewares() []interface{} {
+	if md, ok := ct.sample().(MiddlewareDeclarer); ok {
+		return md.Middlewares()
+	}
+
+	return nil
+}
+
+func (ct *controllerType) create() reflect.Value {
+	if ct.factory != nil {
+		return reflect.ValueOf(ct.factory())
+	}
+
+	if ct.proto.Kind() == reflect.Ptr && ct.proto.Elem().Kind() == reflect.Struct {
+		v := reflect.New(ct.proto.Elem().Type())
+		v.Elem().Set(ct.proto.Elem())
+
+		return v
+	}
+
+	return ct.proto
+}
+
+// action returns a handler calling method of a new controller with hooks, and the name of method
+func (ct *controllerType) action(method string) (GasHandler, string) {
+	m, ok := ct.proto.Type().MethodByName(method)
+	if !ok {
+		panic("gas: controller " + ct.proto.Type().String() + " has no method " + method)
+	}
+	if _, ok := ct.proto.Method(m.Index).Interface().(func(*Context) error); !ok {
+		panic("gas: method " + method + " of controller " + ct.proto.Type().String() + " is not a GasHandler")
+	}
+
+	h := func(ctx *Context) error {
+		v := ct.create()
+		c := v.Interface()
+
+		if p, ok := c.(Preparer); ok {
+			if err := p.Prepare(ctx); err != nil {
+				return err
+			}
+		}
+
+		// panics of the action are recovered by the router after Finish
+		if f, ok := c.(Finisher); ok {
+			defer f.Finish(ctx)
+		}
+
+		return v.Method(m.Index).Interface().(func(*Context) error)(ctx)
+	}
+
+	return h, funcName(m.Func.Interface())
+}
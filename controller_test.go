@@ -0,0 +1,97 @@
+package gas
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+type hookController struct {
+	prefix   string
+	user     string
+	finished *int32
+}
+
+func (hc *hookController) Middlewares() []interface{} {
+	return []interface{}{testGroupMiddleware("controller")}
+}
+
+func (hc *hookController) Prepare(c *Context) error {
+	if c.GetParam("user") == "" {
+		return NewHTTPError(http.StatusUnauthorized)
+	}
+	hc.user = c.GetParam("user")
+
+	return nil
+}
+
+func (hc *hookController) Finish(c *Context) {
+	atomic.AddInt32(hc.finished, 1)
+}
+
+func (hc *hookController) Get(c *Context) error {
+	// user set by a previous request is not kept
+	hc.user += "!"
+
+	return c.STRING(http.StatusOK, hc.prefix+hc.user)
+}
+
+func (hc *hookController) Show(c *Context) error {
+	return c.STRING(http.StatusOK, hc.prefix+hc.user+" "+c.GetParam("hook_id"))
+}
+
+func (hc *hookController) Delete(c *Context) error {
+	panic("delete failed")
+}
+
+func TestController_Hooks(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+
+	var finished int32
+	proto := &hookController{prefix: "hi ", finished: &finished}
+	g.Router.REST("/hello", proto)
+	g.Router.Resource("/hooks", proto)
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/hello").WithQuery("user", "john").
+		Expect().Status(http.StatusOK).
+		Header("X-Group").Equal("controller")
+	e.GET("/hello").WithQuery("user", "tom").
+		Expect().Status(http.StatusOK).Body().Equal("hi tom!")
+	e.GET("/hooks/5").WithQuery("user", "tom").
+		Expect().Status(http.StatusOK).Body().Equal("hi tom 5")
+
+	// action and Finish are not called
+	e.GET("/hello").Expect().Status(http.StatusUnauthorized)
+
+	// Finish is called if the action panics
+	e.DELETE("/hello").WithQuery("user", "tom").Expect().Status(http.StatusInternalServerError)
+
+	as.Equal(int32(4), atomic.LoadInt32(&finished))
+	as.Equal("", proto.user)
+}
+
+func TestController_Factory(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+
+	var finished, created int32
+	g.Router.REST("/hello", ControllerFactory(func() ControllerInterface {
+		atomic.AddInt32(&created, 1)
+		return &hookController{prefix: "hey ", finished: &finished}
+	}))
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/hello").WithQuery("user", "john").
+		Expect().Status(http.StatusOK).Body().Equal("hey john!")
+
+	// one for registration
+	as.Equal(int32(2), atomic.LoadInt32(&created))
+	as.Equal(int32(1), atomic.LoadInt32(&finished))
+}
@@ -0,0 +1,57 @@
+package gas
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HTTPError is an error with http status code,
+// handlers can return it to response the status by error handler.
+//
+// Ex:
+//
+//	func ShowUser(c *gas.Context) error {
+//		u, err := findUser(c.GetParam("id"))
+//		if err != nil {
+//			return gas.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+//		}
+//
+//		return c.JSON(http.StatusOK, u)
+//	}
+type HTTPError struct {
+	Code    int
+	Message string
+
+	// Internal is the original error, it will not be shown to the client
+	Internal error
+}
+
+// NewHTTPError creates HTTPError with code, message is the status text of code by default.
+func NewHTTPError(code int, message ...string) *HTTPError {
+	he := &HTTPError{Code: code, Message: http.StatusText(code)}
+	if len(message) != 0 {
+		he.Message = message[0]
+	}
+
+	return he
+}
+
+// SetInternal sets the original error
+func (he *HTTPError) SetInternal(err error) *HTTPError {
+	he.Internal = err
+	return he
+}
+
+// Error implements error interface
+func (he *HTTPError) Error() string {
+	if he.Internal != nil {
+		return fmt.Sprintf("code=%d, message=%s, internal=%v", he.Code, he.Message, he.Internal)
+	}
+
+	return fmt.Sprintf("code=%d, message=%s", he.Code, he.Message)
+}
+
+// Unwrap returns the internal error
+func (he *HTTPError) Unwrap() error {
+	return he.Internal
+}
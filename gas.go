@@ -87,12 +87,14 @@
 package gas
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-gas/config"
 	"github.com/go-gas/gas/model"
 	"github.com/go-gas/gas/model/MySQL"
 	"github.com/go-gas/logger"
 	"github.com/valyala/fasthttp"
+	"html"
 	"net"
 	"net/http"
 	"os"
@@ -109,6 +111,28 @@ var defaultConfig = map[interface{}]interface{}{
 	"PubDir":     "public",
 	"CrtFile":    "",
 	"KeyFile":    "",
+	// seconds to wait for in-flight requests when Stop is called,
+	// 0 means wait until all of them are finished
+	"ShutdownTimeout": 10,
+	// seconds before the context of Context.StdContext is cancelled,
+	// 0 means it's cancelled only when the request finishes
+	"RequestTimeout": 0,
+	// settings of fasthttp.Server, timeouts are in seconds or duration strings.
+	// Only keys set in config are applied, others keep fasthttp defaults
+	// or values set in code: Name, Concurrency, ReadTimeout, WriteTimeout,
+	// IdleTimeout, MaxRequestBodySize, ReadBufferSize, WriteBufferSize,
+	// MaxConnsPerIP, MaxRequestsPerConn, DisableKeepalive, ReduceMemoryUsage
+	// and StreamRequestBody (pass request body to handlers as a stream,
+	// see Context.StreamUpload).
+	"Server": map[interface{}]interface{}{},
+	// templates are parsed once from Dir and cached, they are reloaded
+	// when changed in DEV mode
+	"View": map[interface{}]interface{}{
+		"Dir":      "",
+		"Ext":      ".html",
+		"Layout":   "",
+		"Partials": "partials",
+	},
 	"Db": map[interface{}]interface{}{
 		"SqlDriver": "MySQL",
 		"Hostname":  "localhost",
@@ -136,6 +160,22 @@ type (
 		Model  *gasModel
 		pool   sync.Pool
 		Logger *logger.Logger
+
+		// Server is built from the Server section of config,
+		// it can be modified before calling Run.
+		Server *fasthttp.Server
+
+		// View is built from the View section of config
+		View *View
+
+		connsMu    sync.Mutex
+		conns      map[net.Conn]fasthttp.ConnState
+		onStart    []LifecycleHook
+		onShutdown []LifecycleHook
+
+		// closing is closed when shutdown begins, to end long-lived streams
+		closing     chan struct{}
+		closingOnce sync.Once
 	}
 
 	gasModel struct {
@@ -146,13 +186,18 @@ type (
 // NotFoundString is 404 http status string
 var default404Body = "404 page not found."
 
+// default405Body is the body of default method not allowed handler
+var default405Body = "405 method not allowed."
+
 // New gas Object
 //
 // Ex:
 //  g := New()
 //  g.Run()
 func New(configPath ...string) *Engine {
-	g := &Engine{}
+	g := &Engine{
+		closing: make(chan struct{}),
+	}
 
 	// init logger
 	if _, err := os.Stat("log/system.log"); os.IsNotExist(err) {
@@ -179,12 +224,30 @@ func New(configPath ...string) *Engine {
 	// set router
 	g.Router = newRouter(g) //&Router{g: g}
 
+	// the server is kept so that it can be shut down later
+	g.Server = &fasthttp.Server{
+		Handler:         g.Router.Handler,
+		ConnState:       g.trackConn,
+		CloseOnShutdown: true,
+	}
+	g.configureServer()
+
+	// set view
+	g.configureView()
+	g.AddTemplateFunc("url", g.Router.URL)
+
 	// set default not found handler
 	g.Router.SetNotFoundHandler(defaultNotFoundHandler)
 
+	// set default method not allowed handler
+	g.Router.SetMethodNotAllowedHandler(defaultMethodNotAllowedHandler)
+
 	// set default panic handler
 	g.Router.SetPanicHandler(defaultPanicHandler)
 
+	// set default error handler
+	g.Router.SetErrorHandler(defaultErrorHandler)
+
 	// set static file path
 	g.Router.StaticPath(g.Config.GetString("PubDir"))
 
@@ -202,6 +265,10 @@ func defaultNotFoundHandler(c *Context) error {
 	return c.STRING(http.StatusNotFound, default404Body)
 }
 
+func defaultMethodNotAllowedHandler(c *Context) error {
+	return c.STRING(http.StatusMethodNotAllowed, default405Body)
+}
+
 func defaultPanicHandler(c *Context, rcv interface{}) error {
 	logStr := fmt.Sprintf("Panic occurred...rcv: %v", rcv)
 	c.gas.Logger.Error(logStr)
@@ -216,9 +283,97 @@ func defaultPanicHandler(c *Context, rcv interface{}) error {
 	return c.STRING(500, output)
 }
 
+// defaultErrorHandler responses HTTPError's code and message, HTTPError wrapped
+// by other errors is found by errors.As, other errors are treated as internal server error.
+// The response is JSON if client accepts it, otherwise HTML.
+func defaultErrorHandler(c *Context, err error) {
+	var he *HTTPError
+	if !errors.As(err, &he) {
+		he = NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+
+	if he.Code >= http.StatusInternalServerError {
+		c.gas.Logger.Error("Error occurred...err: " + err.Error())
+	}
+
+	message := he.Message
+	if he.Internal != nil && c.gas.Config.Get("Mode") == "DEV" {
+		message = he.Internal.Error()
+	}
+
+	c.Response.ResetBody()
+
+	if c.acceptsJSON() {
+		c.JSON(he.Code, H{
+			"code":    he.Code,
+			"message": message,
+		})
+		return
+	}
+
+	c.HTML(he.Code, "<html><head><title>"+strconv.Itoa(he.Code)+" "+http.StatusText(he.Code)+"</title></head>"+
+		"<body><h1>"+strconv.Itoa(he.Code)+" "+http.StatusText(he.Code)+"</h1><p>"+html.EscapeString(message)+"</p></body></html>")
+}
+
 // Load config from file
+//
+// Keys set in the Server section are applied to g.Server again,
+// other fields of g.Server are kept.
 func (g *Engine) LoadConfig(configPath string) {
 	g.Config.Load(configPath)
+	g.configureServer()
+	g.configureView()
+}
+
+// configDuration reads a number from config and returns it as a multiple of unit.
+func (g *Engine) configDuration(key string, unit time.Duration) time.Duration {
+	switch v := g.Config.Get(key).(type) {
+	case int:
+		return time.Duration(v) * unit
+	case int64:
+		return time.Duration(v) * unit
+	case float64:
+		return time.Duration(v * float64(unit))
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Duration(n * float64(unit))
+		}
+	}
+
+	return 0
+}
+
+// configInt reads an integer from config, it returns 0 if key is not set or not a number.
+func (g *Engine) configInt(key string) int {
+	switch v := g.Config.Get(key).(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+
+	return 0
+}
+
+// configBool reads a boolean from config.
+func (g *Engine) configBool(key string) bool {
+	switch v := g.Config.Get(key).(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+
+	return false
 }
 
 // Run attaches the router to a http.Server and starts listening and serving HTTP requests.
@@ -232,7 +387,9 @@ func (g *Engine) Run(addr ...string) (err error) {
 
 	fmt.Println("Server is Listen on: " + listenAddr)
 
-	err = fasthttp.ListenAndServe(listenAddr, g.Router.Handler)
+	err = g.serve(func(s *fasthttp.Server) error {
+		return s.ListenAndServe(listenAddr)
+	})
 	return
 }
 
@@ -252,7 +409,9 @@ func (g *Engine) RunTLS(addr ...string) (err error) {
 
 	fmt.Println("Server is Listen on: " + listenAddr)
 
-	err = fasthttp.ListenAndServeTLS(listenAddr, certFile, keyFile, g.Router.Handler)
+	err = g.serve(func(s *fasthttp.Server) error {
+		return s.ListenAndServeTLS(listenAddr, certFile, keyFile)
+	})
 	return
 }
 
@@ -262,7 +421,9 @@ func (g *Engine) RunTLS(addr ...string) (err error) {
 // The server sets the given file mode for the UNIX addr.
 func (g *Engine) RunUNIX(addr string, mode os.FileMode) (err error) {
 
-	err = fasthttp.ListenAndServeUNIX(addr, mode, g.Router.Handler)
+	err = g.serve(func(s *fasthttp.Server) error {
+		return s.ListenAndServeUNIX(addr, mode)
+	})
 	return
 }
 
@@ -1,12 +1,15 @@
 package gas
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"github.com/gavv/httpexpect"
 	"github.com/go-gas/gas/model/MySQL"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
 	"testing"
@@ -183,6 +186,258 @@ func TestRunUNIX(t *testing.T) {
 	assert.False(t, os.IsNotExist(err))
 }
 
+func TestShutdown(t *testing.T) {
+	as := assert.New(t)
+
+	g := New()
+
+	// set route
+	g.Router.Get("/", indexPage)
+
+	started, stopped := false, false
+	g.OnStart(func(g *Engine) error {
+		started = true
+		return nil
+	})
+	g.OnShutdown(func(g *Engine) error {
+		stopped = true
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Run(":9002")
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	testRequest(t, "http://localhost:9002")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	as.NoError(g.Shutdown(ctx))
+	as.NoError(<-done)
+	as.True(started)
+	as.True(stopped)
+
+	_, err := http.Get("http://localhost:9002")
+	as.Error(err)
+}
+
+func TestShutdownSlowRequest(t *testing.T) {
+	as := assert.New(t)
+
+	g := New()
+	g.Router.Post("/", func(ctx *Context) error {
+		return ctx.STRING(http.StatusOK, string(ctx.PostBody()))
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Run(":9015")
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	conn, err := net.Dial("tcp", "localhost:9015")
+	as.NoError(err)
+	defer conn.Close()
+
+	// the request is still uploading when shutdown begins
+	_, err = conn.Write([]byte("POST / HTTP/1.1\r\nHost: localhost\r\nContent-Length: 10\r\n\r\nhello"))
+	as.NoError(err)
+
+	stopped := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		stopped <- g.Shutdown(ctx)
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	_, err = conn.Write([]byte("world"))
+	as.NoError(err)
+
+	resp, err := ioutil.ReadAll(conn)
+	as.NoError(err)
+	as.Contains(string(resp), "200 OK")
+	as.Contains(string(resp), "helloworld")
+
+	as.NoError(<-stopped)
+	as.NoError(<-done)
+}
+
+func TestStop(t *testing.T) {
+	as := assert.New(t)
+
+	g := New()
+
+	// set route
+	g.Router.Get("/", indexPage)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Run(":9003")
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	testRequest(t, "http://localhost:9003")
+
+	as.NoError(g.Stop())
+	as.NoError(<-done)
+}
+
+func TestOnStartError(t *testing.T) {
+	g := New()
+
+	g.OnStart(func(g *Engine) error {
+		return errors.New("start failed")
+	})
+
+	assert.EqualError(t, g.Run(":9004"), "start failed")
+}
+
+func TestServerConfig(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_server.yaml")
+
+	as.Equal("gas-test", g.Server.Name)
+	as.Equal(1024, g.Server.Concurrency)
+	as.Equal(5*time.Second, g.Server.ReadTimeout)
+	as.Equal(90*time.Second, g.Server.WriteTimeout)
+	as.Equal(time.Minute, g.Server.IdleTimeout)
+	as.Equal(1048576, g.Server.MaxRequestBodySize)
+	as.True(g.Server.DisableKeepalive)
+	as.False(g.Server.ReduceMemoryUsage)
+
+	// override before run
+	g.Server.Name = "override"
+
+	g.Router.Get("/", indexPage)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Run(":9005")
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	resp, err := http.Get("http://localhost:9005")
+	as.NoError(err)
+	resp.Body.Close()
+	as.Equal("override", resp.Header.Get("Server"))
+
+	as.NoError(g.Stop())
+	as.NoError(<-done)
+}
+
+func TestServerConfigReload(t *testing.T) {
+	as := assert.New(t)
+
+	g := New()
+	g.Server.Name = "code"
+	g.Server.MaxRequestBodySize = 32 << 20
+	g.Server.ReduceMemoryUsage = true
+
+	// keys not in config are kept
+	g.LoadConfig("testfiles/config_server.yaml")
+	as.Equal("gas-test", g.Server.Name)
+	as.Equal(1048576, g.Server.MaxRequestBodySize)
+	as.True(g.Server.ReduceMemoryUsage)
+	as.Equal(0, g.Server.ReadBufferSize)
+
+	g.Server.WriteBufferSize = 8192
+	g.LoadConfig("testfiles/config_test.yaml")
+	as.Equal(8192, g.Server.WriteBufferSize)
+	as.Equal(time.Minute, g.Server.IdleTimeout)
+}
+
+func TestRunAll(t *testing.T) {
+	as := assert.New(t)
+
+	g := New()
+
+	// set route
+	g.Router.Get("/", indexPage)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.RunAll(
+			HTTP(":9006"),
+			HTTPS(":9007", "certificate/localhost.cert", "certificate/localhost.key"),
+			UNIX("gas_all.sock", 0644),
+		)
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	testRequest(t, "http://localhost:9006")
+	testRequest(t, "https://localhost:9007")
+
+	_, err := os.Stat("gas_all.sock")
+	as.False(os.IsNotExist(err))
+
+	as.NoError(g.Stop())
+	as.NoError(<-done)
+
+	_, err = http.Get("http://localhost:9006")
+	as.Error(err)
+}
+
+func TestRunAllError(t *testing.T) {
+	as := assert.New(t)
+
+	g := New()
+
+	// the second listener can't bind the same address
+	as.Error(g.RunAll(HTTP(":9008"), HTTP(":9008")))
+
+	// the first one should be closed
+	ln, err := net.Listen("tcp4", ":9008")
+	as.NoError(err)
+	ln.Close()
+
+	// a failed listener shuts down others
+	as.Error(g.RunAll(HTTP(":9008"), HTTPS(":9009", "certificate/not-exist.cert", "certificate/not-exist.key")))
+
+	_, err = http.Get("http://localhost:9008")
+	as.Error(err)
+
+	// no listeners, start hooks are not called
+	started := false
+	g.OnStart(func(g *Engine) error {
+		started = true
+		return nil
+	})
+	as.Equal(errNoListeners, g.RunAll())
+	as.Equal(errNoListeners, g.Serve())
+	as.False(started)
+}
+
+func TestServe(t *testing.T) {
+	as := assert.New(t)
+
+	g := New()
+
+	// set route
+	g.Router.Get("/", indexPage)
+
+	ln1, err := net.Listen("tcp4", ":9010")
+	as.NoError(err)
+	ln2, err := net.Listen("tcp4", ":9011")
+	as.NoError(err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Serve(ln1, ln2)
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	testRequest(t, "http://localhost:9010")
+	testRequest(t, "http://localhost:9011")
+
+	as.NoError(g.Stop())
+	as.NoError(<-done)
+}
+
 func TestGas_NewModel(t *testing.T) {
 	as := assert.New(t)
 
@@ -0,0 +1,161 @@
+package gas
+
+import (
+	"path"
+	"strings"
+)
+
+// RouterGroup is a set of routes sharing the same path prefix and middlewares.
+//
+// Ex:
+//
+//	api := g.Router.Group("/api/v1", authMiddleware)
+//	api.Get("/users", controllers.ListUsers)   // GET /api/v1/users
+//
+//	admin := api.Group("/admin", adminOnly)
+//	admin.Post("/users", controllers.AddUser) // POST /api/v1/admin/users, runs authMiddleware and adminOnly
+type RouterGroup struct {
+	router      *Router
+	parent      *RouterGroup
+	prefix      string
+	middlewares []GasMiddlewareFunc
+
+	// names of middlewares for Routes
+	middlewareNames []string
+}
+
+// Group creates a route group with prefix, the middlewares are run before
+// route's own middlewares for every route in this group.
+// The prefix is cleaned like path.Join, Ex: "api/v1/" is "/api/v1".
+func (r *Router) Group(prefix string, middlewares ...interface{}) *RouterGroup {
+	rg := &RouterGroup{
+		router: r,
+		prefix: strings.TrimSuffix(path.Join("/", prefix), "/"),
+	}
+
+	for _, m := range middlewares {
+		rg.Use(m)
+	}
+
+	return rg
+}
+
+// Group creates a nested route group, its prefix and middlewares are appended to the parent's.
+func (rg *RouterGroup) Group(prefix string, middlewares ...interface{}) *RouterGroup {
+	child := rg.router.Group(rg.prefix+"/"+prefix, middlewares...)
+	child.parent = rg
+
+	return child
+}
+
+// Use adds middleware to the group, it works for all routes in the group
+// and its nested groups, including those registered before.
+func (rg *RouterGroup) Use(m interface{}) {
+	rg.middlewares = append(rg.middlewares, wrapMiddleware(m))
+	rg.middlewareNames = append(rg.middlewareNames, funcName(m))
+	rg.router.rebuild()
+}
+
+// Prefix returns the full path prefix of the group
+func (rg *RouterGroup) Prefix() string {
+	return rg.prefix
+}
+
+// allMiddlewares returns parents' middlewares followed by group's.
+func (rg *RouterGroup) allMiddlewares() []interface{} {
+	var res []interface{}
+	if rg.parent != nil {
+		res = rg.parent.allMiddlewares()
+	}
+
+	for _, m := range rg.middlewares {
+		res = append(res, m)
+	}
+
+	return res
+}
+
+// path joins group prefix and route path, "/" means the prefix itself.
+func (rg *RouterGroup) path(p string) string {
+	if p == "" || p == "/" {
+		if rg.prefix == "" {
+			return "/"
+		}
+
+		return rg.prefix
+	}
+
+	if p[0] != '/' {
+		p = "/" + p
+	}
+
+	return rg.prefix + p
+}
+
+func (rg *RouterGroup) set(method, path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return rg.router.addRoute(&Route{
+		method:      method,
+		path:        rg.path(path),
+		handler:     ch,
+		middlewares: middlewares,
+		group:       rg,
+	})
+}
+
+// Get REST funcs
+func (rg *RouterGroup) Get(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return rg.set("GET", path, ch, middlewares...)
+}
+
+// Post REST funcs
+func (rg *RouterGroup) Post(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return rg.set("POST", path, ch, middlewares...)
+}
+
+// Delete REST funcs
+func (rg *RouterGroup) Delete(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return rg.set("DELETE", path, ch, middlewares...)
+}
+
+// Head REST funcs
+func (rg *RouterGroup) Head(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return rg.set("HEAD", path, ch, middlewares...)
+}
+
+// Options REST funcs
+func (rg *RouterGroup) Options(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return rg.set("OPTIONS", path, ch, middlewares...)
+}
+
+// Put REST funcs
+func (rg *RouterGroup) Put(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return rg.set("PUT", path, ch, middlewares...)
+}
+
+// Patch REST funcs
+func (rg *RouterGroup) Patch(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return rg.set("PATCH", path, ch, middlewares...)
+}
+
+// REST for set all REST route in the group
+func (rg *RouterGroup) REST(path string, c ControllerInterface) {
+	rg.router.rest(rg, rg.path(path), c)
+}
+
+// StaticPath serves files in dir on prefix/dir/*filepath,
+// unlike Router.StaticPath the middlewares are run before serving files.
+func (rg *RouterGroup) StaticPath(dir string) {
+	path := rg.path("/"+dir) + "/*filepath"
+	fsHandler := newStaticHandler(path, dir)
+
+	rg.router.addRoute(&Route{
+		method: "GET",
+		path:   path,
+		handler: func(c *Context) error {
+			fsHandler(c.RequestCtx)
+			return nil
+		},
+		group:       rg,
+		handlerName: "StaticPath(" + dir + ")",
+	})
+}
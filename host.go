@@ -0,0 +1,160 @@
+package gas
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// hostRouter is a router scoped to hosts matching pattern
+type hostRouter struct {
+	pattern string
+	labels  []string
+	router  *Router
+}
+
+// Host returns the router for requests whose host matches pattern, requests of other hosts
+// are handled by g.Router. Labels of pattern like ":tenant" capture the label of host into
+// the path parameter, "*" matches any label. Hosts are matched without port and case.
+// Exact hosts are matched before patterns, and patterns in the order they are added.
+//
+// Global middlewares of g.Router run before the router's own ones, even if they are added later.
+// Not found, method not allowed, error and panic handlers and WebSocketUpgrader of g.Router
+// are used when requests are handled, unless they are set on the router.
+// Route names are shared with g.Router, so URL and Context.URLFor generate paths of host routes,
+// and Routes of g.Router lists them with the host.
+//
+// Ex:
+//
+//	api := g.Host("api.example.com")
+//	api.Get("/users", ListUsers)
+//
+//	tenant := g.Host(":tenant.example.com")
+//	tenant.Get("/", func(c *gas.Context) error {
+//		t, _ := c.PathParam("tenant")
+//		return c.STRING(http.StatusOK, "welcome to "+t)
+//	})
+func (g *Engine) Host(pattern string) *Router {
+	return g.Router.host(pattern)
+}
+
+func (r *Router) host(pattern string) *Router {
+	pattern = strings.ToLower(pattern)
+	for _, h := range r.hosts {
+		if h.pattern == pattern {
+			return h.router
+		}
+	}
+
+	hr := newRouter(r.g)
+	hr.parent = r
+	hr.hostPattern = pattern
+	hr.names = r.names
+	hr.WebSocketUpgrader = nil
+
+	// handlers of r are read for each request, so that changes of them are followed
+	hr.SetNotFoundHandler(func(c *Context) error {
+		if r.notFound == nil {
+			c.Error(fasthttp.StatusMessage(fasthttp.StatusNotFound), fasthttp.StatusNotFound)
+			return nil
+		}
+
+		return r.notFound.handler(c)
+	})
+	hr.SetMethodNotAllowedHandler(func(c *Context) error {
+		if r.methodNotAllowed == nil {
+			c.Error(fasthttp.StatusMessage(fasthttp.StatusMethodNotAllowed), fasthttp.StatusMethodNotAllowed)
+			return nil
+		}
+
+		return r.methodNotAllowed.handler(c)
+	})
+	hr.errorHandler = func(c *Context, err error) {
+		r.errorHandler(c, err)
+	}
+	hr.panicHandler = func(c *Context, rcv interface{}) error {
+		if r.panicHandler == nil {
+			panic(rcv)
+		}
+
+		return r.panicHandler(c, rcv)
+	}
+	hr.PanicHandler = func(fctx *fasthttp.RequestCtx, rcv interface{}) {
+		if r.PanicHandler == nil {
+			panic(rcv)
+		}
+
+		r.PanicHandler(fctx, rcv)
+	}
+
+	h := &hostRouter{
+		pattern: pattern,
+		labels:  strings.Split(pattern, "."),
+		router:  hr,
+	}
+
+	// exact hosts are matched first
+	if strings.ContainsAny(pattern, ":*") {
+		r.hosts = append(r.hosts, h)
+	} else {
+		i := 0
+		for i < len(r.hosts) && !strings.ContainsAny(r.hosts[i].pattern, ":*") {
+			i++
+		}
+		r.hosts = append(r.hosts[:i], append([]*hostRouter{h}, r.hosts[i:]...)...)
+	}
+
+	return hr
+}
+
+// match reports whether host matches the pattern and sets captured labels to ctx
+func (h *hostRouter) match(host string, ctx *fasthttp.RequestCtx) bool {
+	labels := strings.Split(host, ".")
+	if len(labels) != len(h.labels) {
+		return false
+	}
+
+	for i, l := range h.labels {
+		if l == "*" || (l != "" && l[0] == ':' && labels[i] != "") {
+			continue
+		}
+		if l != labels[i] {
+			return false
+		}
+	}
+
+	for i, l := range h.labels {
+		if l != "" && l[0] == ':' {
+			ctx.SetUserValue(l[1:], labels[i])
+		}
+	}
+
+	return true
+}
+
+// Handler dispatches requests to routers of Host by the Host header before path matching,
+// other requests are handled by r.
+func (r *Router) Handler(ctx *fasthttp.RequestCtx) {
+	if len(r.hosts) != 0 {
+		host := string(bytes.ToLower(stripPort(ctx.Request.Header.Host())))
+		for _, h := range r.hosts {
+			if h.match(host, ctx) {
+				h.router.Router.Handler(ctx)
+				return
+			}
+		}
+	}
+
+	r.Router.Handler(ctx)
+}
+
+// stripPort removes port from host, IPv6 addresses are in brackets
+func stripPort(host []byte) []byte {
+	i := bytes.LastIndexByte(host, ':')
+	if i < 0 || bytes.IndexByte(host[i:], ']') >= 0 {
+		return host
+	}
+
+	return host[:i]
+}
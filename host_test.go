@@ -0,0 +1,152 @@
+package gas
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestEngine_Host(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+
+	g.Router.Get("/", func(c *Context) error {
+		return c.STRING(http.StatusOK, "default")
+	})
+
+	api := g.Host("API.example.com")
+	as.Equal(api, g.Host("api.example.com"))
+	api.Use(testGroupMiddleware("api"))
+	api.Get("/users/:id", func(c *Context) error {
+		return c.STRING(http.StatusOK, "api user "+c.GetParam("id"))
+	})
+
+	g.Host(":tenant.example.com").Get("/", func(c *Context) error {
+		tenant, _ := c.PathParam("tenant")
+		return c.STRING(http.StatusOK, "tenant "+tenant)
+	})
+	g.Host("*.static.example.com").Get("/", func(c *Context) error {
+		return c.STRING(http.StatusOK, "static")
+	})
+	// exact hosts are matched before patterns
+	g.Host("www.example.com").Get("/", func(c *Context) error {
+		return c.STRING(http.StatusOK, "www")
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	ee := e.GET("/users/5").WithHost("api.example.com:8080").Expect()
+	ee.Status(http.StatusOK).Body().Equal("api user 5")
+	ee.Header("X-Group").Equal("api")
+
+	e.GET("/").WithHost("www.example.com").
+		Expect().Status(http.StatusOK).Body().Equal("www")
+	e.GET("/").WithHost("Acme.example.com").
+		Expect().Status(http.StatusOK).Body().Equal("tenant acme")
+	e.GET("/").WithHost("a.static.example.com").
+		Expect().Status(http.StatusOK).Body().Equal("static")
+
+	// not found in the host router
+	e.GET("/").WithHost("api.example.com").
+		Expect().Status(http.StatusNotFound).Body().Equal(default404Body)
+	e.POST("/users/5").WithHost("api.example.com").
+		Expect().Status(http.StatusMethodNotAllowed).Header("Allow").Equal("GET, OPTIONS")
+
+	// other hosts fall back to the default router
+	e.GET("/").WithHost("example.com").
+		Expect().Status(http.StatusOK).Body().Equal("default")
+	e.GET("/").WithHost("a.b.example.com").
+		Expect().Status(http.StatusOK).Body().Equal("default")
+	e.GET("/").WithHost("[::1]:8080").
+		Expect().Status(http.StatusOK).Body().Equal("default")
+}
+
+func TestEngine_HostInherit(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+
+	api := g.Host("api.example.com")
+	api.Use(testGroupMiddleware("api"))
+	api.Get("/users/:id", func(c *Context) error {
+		return c.STRING(http.StatusOK, "api user "+c.GetParam("id"))
+	}).Name("api.user")
+	api.Get("/error", func(c *Context) error {
+		return errors.New("failed")
+	})
+	api.Get("/panic", func(c *Context) error {
+		panic("boom")
+	})
+
+	g.Router.Get("/link", func(c *Context) error {
+		u, err := c.URLFor("api.user", 5)
+		if err != nil {
+			return err
+		}
+
+		return c.STRING(http.StatusOK, u)
+	})
+
+	// set on g.Router after the host router is created
+	g.Router.Use(testGroupMiddleware("global"))
+	g.Router.SetNotFoundHandler(func(c *Context) error {
+		return c.STRING(http.StatusNotFound, "custom not found")
+	})
+	g.Router.SetErrorHandler(func(c *Context, err error) {
+		c.STRING(http.StatusTeapot, "custom "+err.Error())
+	})
+	g.Router.SetPanicHandler(func(c *Context, rcv interface{}) error {
+		return c.STRING(http.StatusInternalServerError, "custom panic")
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	ee := e.GET("/users/5").WithHost("api.example.com").Expect()
+	ee.Status(http.StatusOK).Body().Equal("api user 5")
+	as.Equal([]string{"global", "api"}, ee.Raw().Header["X-Group"])
+
+	ee = e.GET("/none").WithHost("api.example.com").Expect()
+	ee.Status(http.StatusNotFound).Body().Equal("custom not found")
+	as.Equal([]string{"global", "api"}, ee.Raw().Header["X-Group"])
+
+	e.GET("/error").WithHost("api.example.com").
+		Expect().Status(http.StatusTeapot).Body().Equal("custom failed")
+	e.GET("/panic").WithHost("api.example.com").
+		Expect().Status(http.StatusInternalServerError).Body().Equal("custom panic")
+
+	// names are shared with g.Router
+	e.GET("/link").Expect().Status(http.StatusOK).Body().Equal("/users/5")
+	u, err := api.URL("api.user", 7)
+	as.Nil(err)
+	as.Equal("/users/7", u)
+	as.Panics(func() {
+		g.Router.Get("/users/:id", indexPage).Name("api.user")
+	})
+
+	// host routes are listed by g.Router
+	as.Contains(g.Router.Routes(), RouteInfo{
+		Host:        "api.example.com",
+		Method:      "GET",
+		Path:        "/users/:id",
+		Name:        "api.user",
+		Handler:     "github.com/go-gas/gas.TestEngine_HostInherit.func1",
+		Middlewares: []string{"github.com/go-gas/gas.testGroupMiddleware.func1", "github.com/go-gas/gas.testGroupMiddleware.func1"},
+	})
+}
+
+func TestRouter_DebugRoutesHost(t *testing.T) {
+	// DEV mode by default
+	g := New()
+	g.Host("api.example.com").Get("/users", indexPage)
+	g.Router.DebugRoutes("/_routes")
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/_routes").Expect().Status(http.StatusOK).
+		Body().Equal("HOST             METHOD  PATH               NAME  HANDLER                          MIDDLEWARES\n" +
+		"                 GET     /public/*filepath        StaticPath(public)\n" +
+		"                 GET     /_routes                 DebugRoutes\n" +
+		"api.example.com  GET     /users                   github.com/go-gas/gas.indexPage\n")
+}
@@ -0,0 +1,206 @@
+package gas
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// PathParam returns path parameter of route, ok is false if it does not exist.
+//
+// Ex:
+//
+//	// r.Get("/users/:id", ShowUser)
+//	id, ok := ctx.PathParam("id")
+func (ctx *Context) PathParam(name string) (string, bool) {
+	v, ok := ctx.UserValue(name).(string)
+
+	return v, ok
+}
+
+// QueryParam returns the first value of name in query string, ok is false if it does not exist.
+func (ctx *Context) QueryParam(name string) (string, bool) {
+	args := ctx.QueryArgs()
+	if !args.Has(name) {
+		return "", false
+	}
+
+	return string(args.Peek(name)), true
+}
+
+// FormParam returns the first value of name in urlencoded or multipart form body,
+// ok is false if it does not exist. Unlike fasthttp.RequestCtx.FormValue
+// query string is not included.
+func (ctx *Context) FormParam(name string) (string, bool) {
+	if args := ctx.PostArgs(); args.Has(name) {
+		return string(args.Peek(name)), true
+	}
+
+	if mf, err := ctx.MultipartForm(); err == nil {
+		if v := mf.Value[name]; len(v) != 0 {
+			return v[0], true
+		}
+	}
+
+	return "", false
+}
+
+// QueryParams returns all values in query string
+func (ctx *Context) QueryParams() map[string][]string {
+	res := make(map[string][]string)
+	ctx.QueryArgs().VisitAll(func(k, v []byte) {
+		res[string(k)] = append(res[string(k)], string(v))
+	})
+
+	return res
+}
+
+// FormParams returns all values in urlencoded or multipart form body, files are not included.
+func (ctx *Context) FormParams() map[string][]string {
+	res := make(map[string][]string)
+	ctx.PostArgs().VisitAll(func(k, v []byte) {
+		res[string(k)] = append(res[string(k)], string(v))
+	})
+
+	if mf, err := ctx.MultipartForm(); err == nil {
+		for k, v := range mf.Value {
+			res[k] = append(res[k], v...)
+		}
+	}
+
+	return res
+}
+
+// invalidParam returns HTTPError with status 400 for invalid parameter value
+func invalidParam(name string, err error) error {
+	return NewHTTPError(http.StatusBadRequest, "invalid parameter "+name).SetInternal(err)
+}
+
+// ParamInt returns path parameter as int, def (or 0) is returned if it does not exist,
+// HTTPError with status 400 is returned if it is not a number.
+//
+// Ex:
+//
+//	// r.Get("/users/:id", ShowUser)
+//	id, err := ctx.ParamInt("id")
+//	if err != nil {
+//		return err
+//	}
+func (ctx *Context) ParamInt(name string, def ...int) (int, error) {
+	v, ok := ctx.PathParam(name)
+	if !ok {
+		if len(def) != 0 {
+			return def[0], nil
+		}
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, invalidParam(name, err)
+	}
+
+	return n, nil
+}
+
+// ParamInt64 returns path parameter as int64, it works like ParamInt.
+func (ctx *Context) ParamInt64(name string, def ...int64) (int64, error) {
+	v, ok := ctx.PathParam(name)
+	if !ok {
+		if len(def) != 0 {
+			return def[0], nil
+		}
+		return 0, nil
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, invalidParam(name, err)
+	}
+
+	return n, nil
+}
+
+// ParamBool returns path parameter as bool, values are parsed by strconv.ParseBool.
+// It works like ParamInt.
+func (ctx *Context) ParamBool(name string, def ...bool) (bool, error) {
+	v, ok := ctx.PathParam(name)
+	if !ok {
+		if len(def) != 0 {
+			return def[0], nil
+		}
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, invalidParam(name, err)
+	}
+
+	return b, nil
+}
+
+// ParamUUID returns path parameter if it is a UUID like "6ba7b810-9dad-11d1-80b4-00c04fd430c8".
+// It works like ParamInt.
+func (ctx *Context) ParamUUID(name string, def ...string) (string, error) {
+	v, ok := ctx.PathParam(name)
+	if !ok {
+		if len(def) != 0 {
+			return def[0], nil
+		}
+		return "", nil
+	}
+
+	if !uuidRegexp.MatchString(v) {
+		return "", NewHTTPError(http.StatusBadRequest, "invalid parameter "+name)
+	}
+
+	return v, nil
+}
+
+// QueryInt returns query parameter as int, it works like ParamInt.
+//
+// Ex:
+//
+//	page, err := ctx.QueryInt("page", 1)
+func (ctx *Context) QueryInt(name string, def ...int) (int, error) {
+	v, ok := ctx.QueryParam(name)
+	if !ok || v == "" {
+		if len(def) != 0 {
+			return def[0], nil
+		}
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, invalidParam(name, err)
+	}
+
+	return n, nil
+}
+
+// QueryTime returns query parameter parsed by layout, it works like ParamInt.
+//
+// Ex:
+//
+//	since, err := ctx.QueryTime("since", "2006-01-02", time.Now().AddDate(0, -1, 0))
+func (ctx *Context) QueryTime(name, layout string, def ...time.Time) (time.Time, error) {
+	v, ok := ctx.QueryParam(name)
+	if !ok || v == "" {
+		if len(def) != 0 {
+			return def[0], nil
+		}
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(layout, v)
+	if err != nil {
+		return time.Time{}, invalidParam(name, err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,117 @@
+package gas
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestContext_GetParamMissing(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	g.Router.Get("/", func(c *Context) error {
+		return c.STRING(http.StatusOK, "["+c.GetParam("missing")+"]")
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/").Expect().Status(http.StatusOK).Body().Equal("[]")
+}
+
+func TestContext_Params(t *testing.T) {
+	as := assert.New(t)
+
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	g.Router.Post("/users/:id/:active/:uuid", func(c *Context) error {
+		v, ok := c.PathParam("id")
+		as.True(ok)
+		as.Equal("10", v)
+		_, ok = c.PathParam("missing")
+		as.False(ok)
+
+		v, ok = c.QueryParam("q")
+		as.True(ok)
+		as.Equal("a", v)
+		v, ok = c.QueryParam("empty")
+		as.True(ok)
+		as.Equal("", v)
+		_, ok = c.QueryParam("missing")
+		as.False(ok)
+
+		v, ok = c.FormParam("name")
+		as.True(ok)
+		as.Equal("gas", v)
+		// query string is not included
+		_, ok = c.FormParam("q")
+		as.False(ok)
+
+		as.Equal(map[string][]string{"q": {"a", "b"}, "empty": {""}, "page": {"2"}, "since": {"2017-01-02"}}, c.QueryParams())
+		as.Equal(map[string][]string{"name": {"gas"}, "tag": {"x", "y"}}, c.FormParams())
+
+		id, err := c.ParamInt("id")
+		as.Nil(err)
+		as.Equal(10, id)
+		id, err = c.ParamInt("missing", 5)
+		as.Nil(err)
+		as.Equal(5, id)
+		id64, err := c.ParamInt64("id")
+		as.Nil(err)
+		as.Equal(int64(10), id64)
+		active, err := c.ParamBool("active")
+		as.Nil(err)
+		as.True(active)
+		uuid, err := c.ParamUUID("uuid")
+		as.Nil(err)
+		as.Equal("6ba7b810-9dad-11d1-80b4-00c04fd430c8", uuid)
+
+		page, err := c.QueryInt("page")
+		as.Nil(err)
+		as.Equal(2, page)
+		page, err = c.QueryInt("empty", 1)
+		as.Nil(err)
+		as.Equal(1, page)
+		since, err := c.QueryTime("since", "2006-01-02")
+		as.Nil(err)
+		as.Equal(time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC), since)
+		def := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		since, err = c.QueryTime("until", "2006-01-02", def)
+		as.Nil(err)
+		as.Equal(def, since)
+
+		return c.NoContent(http.StatusNoContent)
+	})
+	g.Router.Get("/invalid/:id/:active/:uuid", func(c *Context) error {
+		if _, err := c.ParamBool("active"); err == nil {
+			return c.NoContent(http.StatusNoContent)
+		}
+		if _, err := c.ParamUUID("uuid"); err == nil {
+			return c.NoContent(http.StatusNoContent)
+		}
+		if _, err := c.QueryTime("since", "2006-01-02"); err == nil {
+			return c.NoContent(http.StatusNoContent)
+		}
+		_, err := c.ParamInt("id")
+		return err
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.POST("/users/10/true/6ba7b810-9dad-11d1-80b4-00c04fd430c8").
+		WithQuery("q", "a").
+		WithQuery("q", "b").
+		WithQuery("empty", "").
+		WithQuery("page", 2).
+		WithQuery("since", "2017-01-02").
+		WithFormField("name", "gas").
+		WithFormField("tag", "x").
+		WithFormField("tag", "y").
+		Expect().Status(http.StatusNoContent)
+
+	e.GET("/invalid/abc/yes/123").WithQuery("since", "01/02").
+		Expect().Status(http.StatusBadRequest).
+		Body().Contains("invalid parameter id")
+}
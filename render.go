@@ -0,0 +1,366 @@
+package gas
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/vmihailenco/msgpack"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	// jsonpCallbackRegexp matches safe JSONP callback names, Ex: "cb", "jQuery123.handle", "cbs[0]"
+	jsonpCallbackRegexp = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$.\[\]]*$`)
+
+	errInvalidRedirectCode = errors.New("invalid redirect status code")
+)
+
+// XML encodes data to xml and writes it with status
+func (ctx *Context) XML(status int, data interface{}) error {
+	b, err := xml.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return ctx.writeXML(status, ApplicationXMLCharsetUTF8, b)
+}
+
+func (ctx *Context) writeXML(status int, contentType string, b []byte) error {
+	ctx.SetContentType(contentType)
+	ctx.SetStatusCode(status)
+	ctx.WriteString(xml.Header)
+	_, err := ctx.Write(b)
+
+	return err
+}
+
+// JSONP encodes data to json and wraps it with callback, Ex: callback({"a":1});
+// The callback name is checked, HTTPError with status 400 is returned if it is unsafe.
+//
+// Ex:
+//
+//	return ctx.JSONP(http.StatusOK, string(ctx.QueryArgs().Peek("callback")), data)
+func (ctx *Context) JSONP(status int, callback string, data interface{}) error {
+	if !jsonpCallbackRegexp.MatchString(callback) {
+		return NewHTTPError(http.StatusBadRequest, "invalid JSONP callback")
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	ctx.SetContentType(ApplicationJavaScriptCharsetUTF8)
+	ctx.SetStatusCode(status)
+	ctx.WriteString(callback + "(")
+	ctx.Write(b)
+	_, err = ctx.WriteString(");")
+
+	return err
+}
+
+// YAML encodes data to yaml and writes it with status
+func (ctx *Context) YAML(status int, data interface{}) error {
+	b, err := yaml.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	ctx.SetContentType(ApplicationYAMLCharsetUTF8)
+	ctx.SetStatusCode(status)
+	_, err = ctx.Write(b)
+
+	return err
+}
+
+// Msgpack encodes data to msgpack and writes it with status
+func (ctx *Context) Msgpack(status int, data interface{}) error {
+	b, err := msgpack.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	ctx.SetContentType(ApplicationMsgpack)
+	ctx.SetStatusCode(status)
+	_, err = ctx.Write(b)
+
+	return err
+}
+
+// ProtoBuf encodes data to protocol buffers and writes it with status
+func (ctx *Context) ProtoBuf(status int, data proto.Message) error {
+	b, err := proto.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	ctx.SetContentType(ApplicationProtobuf)
+	ctx.SetStatusCode(status)
+	_, err = ctx.Write(b)
+
+	return err
+}
+
+// negotiateOffers are media types Negotiate can render, in preference order
+// when client accepts them equally, protobuf types must be the last two.
+var negotiateOffers = []string{
+	ApplicationJSON,
+	ApplicationXML,
+	TextXML,
+	ApplicationYAML,
+	"application/yaml",
+	"text/yaml",
+	ApplicationMsgpack,
+	"application/x-msgpack",
+	ApplicationProtobuf,
+	"application/x-protobuf",
+}
+
+// Negotiate renders data by the media type client prefers in Accept header,
+// JSON, XML, YAML, msgpack and protobuf (if data is a proto.Message) are supported.
+// JSON is used if Accept header is absent, HTTPError with status 406
+// is returned if none of them is acceptable.
+// Data which can not be encoded to XML, like maps, is rendered by the next acceptable type.
+//
+// Ex:
+//
+//	// Accept: application/xml;q=0.9, application/json;q=0.8
+//	return ctx.Negotiate(http.StatusOK, user) // renders xml
+func (ctx *Context) Negotiate(status int, data interface{}) error {
+	pm, isProto := data.(proto.Message)
+
+	offers := negotiateOffers
+	if !isProto {
+		offers = offers[:len(offers)-2]
+	}
+
+	format := ctx.NegotiateFormat(offers...)
+	if format == ApplicationXML || format == TextXML {
+		b, err := xml.Marshal(data)
+		if err == nil {
+			if format == TextXML {
+				return ctx.writeXML(status, TextXMLCharsetUTF8, b)
+			}
+
+			return ctx.writeXML(status, ApplicationXMLCharsetUTF8, b)
+		}
+
+		format = ctx.NegotiateFormat(withoutXML(offers)...)
+	}
+
+	switch format {
+	case ApplicationJSON:
+		return ctx.JSON(status, data)
+	case ApplicationYAML, "application/yaml", "text/yaml":
+		return ctx.YAML(status, data)
+	case ApplicationMsgpack, "application/x-msgpack":
+		return ctx.Msgpack(status, data)
+	case ApplicationProtobuf, "application/x-protobuf":
+		return ctx.ProtoBuf(status, pm)
+	}
+
+	return NewHTTPError(http.StatusNotAcceptable)
+}
+
+// withoutXML returns offers except xml types
+func withoutXML(offers []string) []string {
+	res := make([]string, 0, len(offers))
+	for _, o := range offers {
+		if o != ApplicationXML && o != TextXML {
+			res = append(res, o)
+		}
+	}
+
+	return res
+}
+
+// NegotiateFormat returns the offer client prefers by q-values in Accept header,
+// earlier offers are preferred if q-values are the same.
+// The first offer is returned if Accept header is absent,
+// empty string is returned if none of offers is acceptable.
+func (ctx *Context) NegotiateFormat(offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+
+	accept := ctx.Request.Header.Peek("Accept")
+	if len(accept) == 0 {
+		return offers[0]
+	}
+
+	specs := parseAccept(string(accept))
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := acceptQuality(specs, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+type acceptSpec struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses Accept header, specs are sorted by specificity,
+// so exact types are matched before "type/*" and "*/*".
+func parseAccept(accept string) []acceptSpec {
+	var specs []acceptSpec
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+
+		spec := acceptSpec{mediaType: strings.ToLower(strings.TrimSpace(params[0])), q: 1}
+		if spec.mediaType == "" {
+			continue
+		}
+
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if q, err := strconv.ParseFloat(p[2:], 64); err == nil && q >= 0 && q <= 1 {
+					spec.q = q
+				}
+			}
+		}
+
+		specs = append(specs, spec)
+	}
+
+	sort.SliceStable(specs, func(i, j int) bool {
+		return specificity(specs[i].mediaType) > specificity(specs[j].mediaType)
+	})
+
+	return specs
+}
+
+func specificity(mediaType string) int {
+	switch {
+	case mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(mediaType, "/*"):
+		return 1
+	}
+
+	return 2
+}
+
+// acceptQuality returns q-value of the most specific spec matching offer, 0 if not matched
+func acceptQuality(specs []acceptSpec, offer string) float64 {
+	for _, s := range specs {
+		switch {
+		case s.mediaType == offer, s.mediaType == "*/*":
+		case strings.HasSuffix(s.mediaType, "/*") && strings.HasPrefix(offer, s.mediaType[:len(s.mediaType)-1]):
+		default:
+			continue
+		}
+
+		return s.q
+	}
+
+	return 0
+}
+
+// Redirect redirects to url with status code, code must be 3xx,
+// relative url is resolved by the request uri.
+// Note the order of arguments is different from fasthttp.RequestCtx.Redirect.
+func (ctx *Context) Redirect(code int, url string) error {
+	if code < http.StatusMultipleChoices || code > http.StatusPermanentRedirect {
+		return errInvalidRedirectCode
+	}
+
+	ctx.RequestCtx.Redirect(url, code)
+
+	return nil
+}
+
+// NoContent responds status code without body
+func (ctx *Context) NoContent(code int) error {
+	ctx.SetStatusCode(code)
+	ctx.Response.ResetBody()
+
+	return nil
+}
+
+// Blob writes b with status code and content type
+func (ctx *Context) Blob(code int, contentType string, b []byte) error {
+	ctx.SetContentType(contentType)
+	ctx.SetStatusCode(code)
+	_, err := ctx.Write(b)
+
+	return err
+}
+
+// Stream writes data read from r with status code and content type,
+// r is read after the handler returns and closed at last if it is an io.Closer.
+func (ctx *Context) Stream(code int, contentType string, r io.Reader) error {
+	ctx.SetContentType(contentType)
+	ctx.SetStatusCode(code)
+	ctx.SetBodyStream(r, -1)
+
+	return nil
+}
+
+// File sends file of path, content type is detected by file extension,
+// range requests are supported. HTTPError with status 404 is returned
+// if file does not exist or it is a directory.
+func (ctx *Context) File(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return NewHTTPError(http.StatusNotFound).SetInternal(err)
+	}
+
+	ctx.SendFile(path)
+
+	return nil
+}
+
+// Attachment sends file of path to be downloaded as name
+//
+// Ex:
+//
+//	return ctx.Attachment("storage/reports/1.pdf", "report 2017.pdf")
+func (ctx *Context) Attachment(path, name string) error {
+	ctx.Response.Header.Set(ContentDisposition, contentDisposition("attachment", name))
+
+	return ctx.File(path)
+}
+
+// contentDisposition formats Content-Disposition header by RFC 6266,
+// non-ASCII names are also set in filename* parameter.
+func contentDisposition(dispositionType, name string) string {
+	ascii := true
+	for i := 0; i < len(name); i++ {
+		if name[i] >= utf8.RuneSelf || name[i] < ' ' {
+			ascii = false
+			break
+		}
+	}
+
+	quoted := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name)
+	if ascii {
+		return dispositionType + `; filename="` + quoted + `"`
+	}
+
+	fallback := strings.Map(func(r rune) rune {
+		if r >= utf8.RuneSelf || r < ' ' {
+			return '_'
+		}
+		return r
+	}, quoted)
+
+	return dispositionType + `; filename="` + fallback + `"; filename*=UTF-8''` + url.PathEscape(name)
+}
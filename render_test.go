@@ -0,0 +1,162 @@
+package gas
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack"
+	"gopkg.in/yaml.v2"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type renderUser struct {
+	Name string `json:"name" xml:"name" yaml:"name" msgpack:"name"`
+	Age  int    `json:"age" xml:"age" yaml:"age" msgpack:"age"`
+}
+
+func TestContext_Renderers(t *testing.T) {
+	as := assert.New(t)
+
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	u := &renderUser{Name: "gas", Age: 2}
+	g.Router.Get("/xml", func(ctx *Context) error {
+		return ctx.XML(http.StatusCreated, u)
+	})
+	g.Router.Get("/jsonp", func(ctx *Context) error {
+		return ctx.JSONP(http.StatusOK, string(ctx.QueryArgs().Peek("callback")), u)
+	})
+	g.Router.Get("/yaml", func(ctx *Context) error {
+		return ctx.YAML(http.StatusOK, u)
+	})
+	g.Router.Get("/msgpack", func(ctx *Context) error {
+		return ctx.Msgpack(http.StatusOK, u)
+	})
+	g.Router.Get("/protobuf", func(ctx *Context) error {
+		return ctx.ProtoBuf(http.StatusOK, &wrappers.StringValue{Value: "gas"})
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/xml").Expect().Status(http.StatusCreated).ContentType(ApplicationXML, "utf-8").
+		Body().Equal(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<renderUser><name>gas</name><age>2</age></renderUser>`)
+
+	e.GET("/jsonp").WithQuery("callback", "jQuery1.cb").Expect().Status(http.StatusOK).
+		ContentType(ApplicationJavaScript, "utf-8").
+		Body().Equal(`jQuery1.cb({"name":"gas","age":2});`)
+	e.GET("/jsonp").WithQuery("callback", "alert(1)//").Expect().Status(http.StatusBadRequest)
+
+	body := e.GET("/yaml").Expect().Status(http.StatusOK).ContentType(ApplicationYAML, "utf-8").Body().Raw()
+	yu := &renderUser{}
+	as.Nil(yaml.Unmarshal([]byte(body), yu))
+	as.Equal(u, yu)
+
+	body = e.GET("/msgpack").Expect().Status(http.StatusOK).ContentType(ApplicationMsgpack).Body().Raw()
+	mu := &renderUser{}
+	as.Nil(msgpack.Unmarshal([]byte(body), mu))
+	as.Equal(u, mu)
+
+	body = e.GET("/protobuf").Expect().Status(http.StatusOK).ContentType(ApplicationProtobuf).Body().Raw()
+	pm := &wrappers.StringValue{}
+	as.Nil(proto.Unmarshal([]byte(body), pm))
+	as.Equal("gas", pm.Value)
+}
+
+func TestContext_Negotiate(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	g.Router.Get("/", func(ctx *Context) error {
+		return ctx.Negotiate(http.StatusOK, &renderUser{Name: "gas", Age: 2})
+	})
+	g.Router.Get("/proto", func(ctx *Context) error {
+		return ctx.Negotiate(http.StatusOK, &wrappers.StringValue{Value: "gas"})
+	})
+	g.Router.Get("/map", func(ctx *Context) error {
+		return ctx.Negotiate(http.StatusOK, H{"name": "gas"})
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	// maps can not be encoded to xml
+	e.GET("/map").WithHeader("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8").
+		Expect().Status(http.StatusOK).ContentType(ApplicationJSON).JSON().Equal(H{"name": "gas"})
+	e.GET("/map").WithHeader("Accept", "application/xml, application/x-yaml;q=0.5").
+		Expect().Status(http.StatusOK).ContentType(ApplicationYAML)
+	e.GET("/map").WithHeader("Accept", "application/xml").
+		Expect().Status(http.StatusNotAcceptable)
+
+	e.GET("/").Expect().Status(http.StatusOK).ContentType(ApplicationJSON)
+	e.GET("/").WithHeader("Accept", "*/*").Expect().ContentType(ApplicationJSON)
+	e.GET("/").WithHeader("Accept", "text/html, application/xml;q=0.9, */*;q=0.8").
+		Expect().ContentType(ApplicationXML)
+	e.GET("/").WithHeader("Accept", "application/json;q=0.5, application/x-yaml").
+		Expect().ContentType(ApplicationYAML)
+	e.GET("/").WithHeader("Accept", "application/*;q=0.2, application/msgpack;q=0.3").
+		Expect().ContentType(ApplicationMsgpack)
+	e.GET("/").WithHeader("Accept", "text/*, application/json;q=0.1").
+		Expect().ContentType(TextXML)
+	e.GET("/").WithHeader("Accept", "application/json;q=0, text/html").
+		Expect().Status(http.StatusNotAcceptable)
+	e.GET("/").WithHeader("Accept", ApplicationProtobuf).
+		Expect().Status(http.StatusNotAcceptable)
+	e.GET("/proto").WithHeader("Accept", ApplicationProtobuf).
+		Expect().Status(http.StatusOK).ContentType(ApplicationProtobuf)
+}
+
+func TestContext_ResponseHelpers(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	g.Router.Post("/string", func(ctx *Context) error {
+		return ctx.STRING(http.StatusOK, "posted")
+	})
+	g.Router.Get("/redirect", func(ctx *Context) error {
+		return ctx.Redirect(http.StatusFound, "/string")
+	})
+	g.Router.Get("/redirect/invalid", func(ctx *Context) error {
+		return ctx.Redirect(http.StatusOK, "/string")
+	})
+	g.Router.Delete("/nocontent", func(ctx *Context) error {
+		ctx.WriteString("ignored")
+		return ctx.NoContent(http.StatusNoContent)
+	})
+	g.Router.Get("/blob", func(ctx *Context) error {
+		return ctx.Blob(http.StatusOK, "image/png", []byte{0x89, 'P', 'N', 'G'})
+	})
+	g.Router.Get("/stream", func(ctx *Context) error {
+		return ctx.Stream(http.StatusOK, TextPlainCharsetUTF8, strings.NewReader("streamed data"))
+	})
+	g.Router.Get("/file", func(ctx *Context) error {
+		return ctx.File("testfiles/static.txt")
+	})
+	g.Router.Get("/file/missing", func(ctx *Context) error {
+		return ctx.File("testfiles/missing.txt")
+	})
+	g.Router.Get("/attachment", func(ctx *Context) error {
+		return ctx.Attachment("testfiles/static.txt", string(ctx.QueryArgs().Peek("name")))
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.POST("/string").Expect().Status(http.StatusOK).ContentType(TextPlain, "utf-8").Body().Equal("posted")
+
+	e.GET("/redirect").Expect().Status(http.StatusFound).Header("Location").Equal("http://example.com/string")
+	e.GET("/redirect/invalid").Expect().Status(http.StatusInternalServerError)
+
+	e.DELETE("/nocontent").Expect().Status(http.StatusNoContent).Body().Empty()
+
+	e.GET("/blob").Expect().Status(http.StatusOK).ContentType("image/png").Body().Equal("\x89PNG")
+	e.GET("/stream").Expect().Status(http.StatusOK).Body().Equal("streamed data")
+
+	e.GET("/file").Expect().Status(http.StatusOK).ContentType(TextPlain).Body().Equal("This is a static file")
+	e.GET("/file/missing").Expect().Status(http.StatusNotFound)
+
+	e.GET("/attachment").WithQuery("name", `a "b".txt`).Expect().Status(http.StatusOK).
+		Header(ContentDisposition).Equal(`attachment; filename="a \"b\".txt"`)
+	e.GET("/attachment").WithQuery("name", "報告.txt").Expect().Status(http.StatusOK).
+		Header(ContentDisposition).Equal(`attachment; filename="__.txt"; filename*=UTF-8''%E5%A0%B1%E5%91%8A.txt`)
+}
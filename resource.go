@@ -0,0 +1,323 @@
+package gas
+
+import (
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	// Indexer lists the collection of a resource, GET /users
+	Indexer interface {
+		Index(*Context) error
+	}
+
+	// Shower shows a member of a resource, GET /users/:user_id
+	Shower interface {
+		Show(*Context) error
+	}
+
+	// Creator creates a member of a resource, POST /users
+	Creator interface {
+		Create(*Context) error
+	}
+
+	// Updater updates a member of a resource, PUT and PATCH /users/:user_id
+	Updater interface {
+		Update(*Context) error
+	}
+
+	// Destroyer deletes a member of a resource, DELETE /users/:user_id
+	Destroyer interface {
+		Destroy(*Context) error
+	}
+
+	// Newer shows the form to create a member, GET /users/new
+	Newer interface {
+		New(*Context) error
+	}
+
+	// Editor shows the form to edit a member, GET /users/:user_id/edit
+	Editor interface {
+		Edit(*Context) error
+	}
+
+	// Resource registers conventional routes of a REST controller, it's created by Router.Resource.
+	// Routes are named by static segments of path and action, like "users.posts.show".
+	Resource struct {
+		router      *Router
+		group       *RouterGroup
+		path        string
+		param       string
+		name        string
+		middlewares []interface{}
+
+		// dispatchers of the member path by method
+		dispatchers map[string]*memberDispatcher
+	}
+
+	// memberDispatcher is registered on the member path, it serves single segment
+	// collection actions by the value of member parameter, because they conflict with
+	// the parameter in fasthttprouter, other values are served by the member route.
+	memberDispatcher struct {
+		method  string
+		path    string
+		member  *Route
+		actions map[string]*Route
+	}
+)
+
+// Resource registers routes for actions the controller implements, they are called on
+// a new controller for each request like REST. The member parameter
+// is the singular of the last segment of path with "_id", like :user_id for "/users".
+// Middlewares are run for all routes of the resource and its nested resources.
+//
+//	GET    /users                Index    users.index
+//	GET    /users/new            New      users.new
+//	POST   /users                Create   users.create
+//	GET    /users/:user_id       Show     users.show
+//	GET    /users/:user_id/edit  Edit     users.edit
+//	PUT    /users/:user_id       Update   users.update
+//	PATCH  /users/:user_id       Update
+//	DELETE /users/:user_id       Destroy  users.destroy
+//
+// Ex:
+//
+//	users := g.Router.Resource("/users", &UserController{}, authMiddleware)
+//	users.Resource("/posts", &PostController{}) // /users/:user_id/posts/:post_id
+//	users.Member("POST", "ban", BanUser)       // /users/:user_id/ban
+//	users.Collection("GET", "search", Search)  // /users/search
+func (r *Router) Resource(path string, c ControllerInterface, middlewares ...interface{}) *Resource {
+	return newResource(r, nil, path, c, middlewares)
+}
+
+// Resource registers routes of the controller in the group, see Router.Resource.
+func (rg *RouterGroup) Resource(path string, c ControllerInterface, middlewares ...interface{}) *Resource {
+	return newResource(rg.router, rg, rg.path(path), c, middlewares)
+}
+
+// Resource registers a nested resource under the member path,
+// Ex: "/posts" of "/users" is "/users/:user_id/posts".
+func (res *Resource) Resource(path string, c ControllerInterface, middlewares ...interface{}) *Resource {
+	mws := append(append([]interface{}{}, res.middlewares...), middlewares...)
+
+	return newResource(res.router, res.group, res.memberPath()+"/"+strings.Trim(path, "/"), c, mws)
+}
+
+func newResource(r *Router, rg *RouterGroup, path string, c ControllerInterface, middlewares []interface{}) *Resource {
+	path = "/" + strings.Trim(path, "/")
+
+	var names []string
+	for _, seg := range strings.Split(path[1:], "/") {
+		if seg != "" && seg[0] != ':' && seg[0] != '*' {
+			names = append(names, seg)
+		}
+	}
+	if len(names) == 0 {
+		panic("gas: resource path " + path + " has no name")
+	}
+
+	res := &Resource{
+		router:      r,
+		group:       rg,
+		path:        path,
+		param:       singular(names[len(names)-1]) + "_id",
+		name:        strings.Join(names, "."),
+		middlewares: middlewares,
+		dispatchers: make(map[string]*memberDispatcher),
+	}
+
+	ct := newControllerType(c)
+	sample := ct.sample()
+	mws := ct.middlewares()
+
+	n := 0
+	if _, ok := sample.(Indexer); ok {
+		res.add(res.route("GET", path, ct, "Index", mws), "index")
+		n++
+	}
+	if _, ok := sample.(Creator); ok {
+		res.add(res.route("POST", path, ct, "Create", mws), "create")
+		n++
+	}
+	if _, ok := sample.(Shower); ok {
+		res.member(res.route("GET", res.memberPath(), ct, "Show", mws), "show")
+		n++
+	}
+	if _, ok := sample.(Updater); ok {
+		res.member(res.route("PUT", res.memberPath(), ct, "Update", mws), "update")
+		res.member(res.route("PATCH", res.memberPath(), ct, "Update", mws), "")
+		n++
+	}
+	if _, ok := sample.(Destroyer); ok {
+		res.member(res.route("DELETE", res.memberPath(), ct, "Destroy", mws), "destroy")
+		n++
+	}
+	if _, ok := sample.(Newer); ok {
+		res.collection(res.route("GET", path+"/new", ct, "New", mws), "new")
+		n++
+	}
+	if _, ok := sample.(Editor); ok {
+		res.add(res.route("GET", res.memberPath()+"/edit", ct, "Edit", mws), "edit")
+		n++
+	}
+
+	if n == 0 {
+		panic("gas: controller of resource " + path + " has no actions")
+	}
+
+	return res
+}
+
+// Path returns the collection path of the resource
+func (res *Resource) Path() string {
+	return res.path
+}
+
+// Param returns the name of member parameter, like "user_id"
+func (res *Resource) Param() string {
+	return res.param
+}
+
+func (res *Resource) memberPath() string {
+	return res.path + "/:" + res.param
+}
+
+// Member registers an action on the member path, Ex: "ban" of "/users" is "/users/:user_id/ban".
+// The route is named like "users.ban".
+func (res *Resource) Member(method, action string, h GasHandler, middlewares ...interface{}) *Resource {
+	action = strings.Trim(action, "/")
+	res.add(res.newRoute(method, res.memberPath()+"/"+action, h, middlewares), action)
+
+	return res
+}
+
+// Collection registers an action on the collection path, Ex: "search" of "/users" is "/users/search".
+// The route is named like "users.search".
+func (res *Resource) Collection(method, action string, h GasHandler, middlewares ...interface{}) *Resource {
+	action = strings.Trim(action, "/")
+	res.collection(res.newRoute(method, res.path+"/"+action, h, middlewares), action)
+
+	return res
+}
+
+func (res *Resource) newRoute(method, path string, h GasHandler, middlewares []interface{}) *Route {
+	return &Route{
+		method:      method,
+		path:        path,
+		handler:     h,
+		middlewares: append(append([]interface{}{}, res.middlewares...), middlewares...),
+		group:       res.group,
+	}
+}
+
+// route creates a route calling method of controller
+func (res *Resource) route(method, path string, ct *controllerType, action string, middlewares []interface{}) *Route {
+	h, name := ct.action(action)
+	rt := res.newRoute(method, path, h, middlewares)
+	rt.handlerName = name
+
+	return rt
+}
+
+func (res *Resource) add(rt *Route, action string) {
+	res.router.addRoute(rt)
+	rt.Name(res.name + "." + action)
+}
+
+// collection adds rt of action on collection path, single segment actions are
+// dispatched on the member path, whether member routes of the method are added before or after.
+func (res *Resource) collection(rt *Route, action string) {
+	if strings.Contains(action, "/") {
+		res.router.addRoute(rt)
+	} else {
+		res.router.register(rt)
+		res.dispatcher(rt.method).actions[action] = rt
+	}
+	rt.Name(res.name + "." + action)
+}
+
+// member adds rt on the member path
+func (res *Resource) member(rt *Route, action string) {
+	res.router.register(rt)
+	if action != "" {
+		rt.Name(res.name + "." + action)
+	}
+
+	res.dispatcher(rt.method).member = rt
+}
+
+// dispatcher returns the dispatcher of method, it's registered on the member path when created.
+// Requests not matching any action are handled as not allowed if there is no member route
+// but other methods are allowed, otherwise as not found.
+func (res *Resource) dispatcher(method string) *memberDispatcher {
+	if d, ok := res.dispatchers[method]; ok {
+		return d
+	}
+
+	d := &memberDispatcher{method: method, path: res.memberPath(), actions: make(map[string]*Route)}
+	res.dispatchers[method] = d
+
+	r := res.router
+	r.dispatchers = append(r.dispatchers, d)
+
+	param := res.param
+	r.Handle(method, d.path, func(ctx *fasthttp.RequestCtx) {
+		if id, ok := ctx.UserValue(param).(string); ok {
+			if a, ok := d.actions[id]; ok {
+				r.serve(ctx, a.chain)
+				return
+			}
+		}
+
+		if d.member != nil {
+			r.serve(ctx, d.member.chain)
+			return
+		}
+
+		// 405 if other methods of the path are allowed
+		r.handleNotFound(ctx)
+	})
+
+	return d
+}
+
+// serves reports whether path is served by d, matched is false if path doesn't match the member path.
+func (d *memberDispatcher) serves(path string) (matched, served bool) {
+	segs := strings.Split(path, "/")
+	pattern := strings.Split(d.path, "/")
+	if len(segs) != len(pattern) {
+		return false, false
+	}
+
+	for i, p := range pattern {
+		if p != "" && p[0] == ':' {
+			if segs[i] == "" {
+				return false, false
+			}
+		} else if p != segs[i] {
+			return false, false
+		}
+	}
+
+	_, ok := d.actions[segs[len(segs)-1]]
+
+	return true, d.member != nil || ok
+}
+
+// singular returns the singular form of an English plural noun for common cases
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "zes"),
+		strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "ss"):
+		return s
+	case strings.HasSuffix(s, "s") && len(s) > 1:
+		return s[:len(s)-1]
+	}
+
+	return s
+}
@@ -0,0 +1,205 @@
+package gas
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"net/http"
+	"testing"
+)
+
+type userResource struct{}
+
+func (u *userResource) Index(c *Context) error {
+	return c.STRING(http.StatusOK, "index")
+}
+func (u *userResource) New(c *Context) error {
+	return c.STRING(http.StatusOK, "new")
+}
+func (u *userResource) Create(c *Context) error {
+	return c.STRING(http.StatusCreated, "create")
+}
+func (u *userResource) Show(c *Context) error {
+	return c.STRING(http.StatusOK, "show "+c.GetParam("user_id"))
+}
+func (u *userResource) Edit(c *Context) error {
+	return c.STRING(http.StatusOK, "edit "+c.GetParam("user_id"))
+}
+func (u *userResource) Update(c *Context) error {
+	return c.STRING(http.StatusOK, "update "+c.GetParam("user_id"))
+}
+func (u *userResource) Destroy(c *Context) error {
+	return c.NoContent(http.StatusNoContent)
+}
+
+type postResource struct{}
+
+func (p *postResource) Index(c *Context) error {
+	return c.STRING(http.StatusOK, "posts of "+c.GetParam("user_id"))
+}
+func (p *postResource) Show(c *Context) error {
+	return c.STRING(http.StatusOK, "post "+c.GetParam("post_id")+" of "+c.GetParam("user_id"))
+}
+
+func TestRouter_Resource(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+
+	users := g.Router.Resource("/users", &userResource{}, testGroupMiddleware("users"))
+	users.Resource("/posts", &postResource{}, testGroupMiddleware("posts"))
+	users.Collection("GET", "search", func(c *Context) error {
+		return c.STRING(http.StatusOK, "search")
+	})
+	users.Collection("POST", "import", func(c *Context) error {
+		return c.STRING(http.StatusOK, "import")
+	})
+	users.Member("POST", "ban", func(c *Context) error {
+		return c.STRING(http.StatusOK, "ban "+c.GetParam("user_id"))
+	})
+
+	as.Equal("/users", users.Path())
+	as.Equal("user_id", users.Param())
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/users").Expect().Status(http.StatusOK).Body().Equal("index")
+	e.GET("/users/new").Expect().Status(http.StatusOK).Body().Equal("new")
+	e.GET("/users/search").Expect().Status(http.StatusOK).Body().Equal("search")
+	e.POST("/users").Expect().Status(http.StatusCreated).Body().Equal("create")
+	e.POST("/users/import").Expect().Status(http.StatusOK).Body().Equal("import")
+	e.GET("/users/5").Expect().Status(http.StatusOK).
+		Header("X-Group").Equal("users")
+	e.GET("/users/5").Expect().Body().Equal("show 5")
+	e.GET("/users/5/edit").Expect().Status(http.StatusOK).Body().Equal("edit 5")
+	e.PUT("/users/5").Expect().Status(http.StatusOK).Body().Equal("update 5")
+	e.PATCH("/users/5").Expect().Status(http.StatusOK).Body().Equal("update 5")
+	e.DELETE("/users/5").Expect().Status(http.StatusNoContent)
+	e.POST("/users/5/ban").Expect().Status(http.StatusOK).Body().Equal("ban 5")
+
+	// nested resource runs middlewares of parent
+	e.GET("/users/5/posts").Expect().Status(http.StatusOK).Body().Equal("posts of 5")
+	e.GET("/users/5/posts/7").Expect().Status(http.StatusOK).
+		Header("X-Group").Equal("users")
+	e.GET("/users/5/posts/7").Expect().Body().Equal("post 7 of 5")
+
+	u, err := g.Router.URL("users.posts.show", 5, 7)
+	as.Nil(err)
+	as.Equal("/users/5/posts/7", u)
+	u, _ = g.Router.URL("users.new")
+	as.Equal("/users/new", u)
+	u, _ = g.Router.URL("users.edit", 5)
+	as.Equal("/users/5/edit", u)
+	u, _ = g.Router.URL("users.search")
+	as.Equal("/users/search", u)
+
+	// dispatched actions are listed too
+	var paths []string
+	for _, ri := range g.Router.Routes() {
+		if ri.Method == "GET" {
+			paths = append(paths, ri.Path)
+		}
+	}
+	as.Contains(paths, "/users/new")
+	as.Contains(paths, "/users/search")
+
+	as.Panics(func() {
+		g.Router.Resource("/empty", &testController{})
+	})
+}
+
+type bookResource struct{}
+
+func (b *bookResource) Index(c *Context) error {
+	return c.STRING(http.StatusOK, "books")
+}
+
+func TestResource_CollectionBeforeMember(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+
+	// no member route of GET and POST yet
+	books := g.Router.Resource("/books", &bookResource{})
+	books.Collection("GET", "search", func(c *Context) error {
+		return c.STRING(http.StatusOK, "search")
+	})
+	books.Collection("POST", "import", func(c *Context) error {
+		return c.STRING(http.StatusOK, "import")
+	})
+	books.Member("GET", "reviews", func(c *Context) error {
+		return c.STRING(http.StatusOK, "reviews of "+c.GetParam("book_id"))
+	})
+	books.Member("POST", "borrow", func(c *Context) error {
+		return c.STRING(http.StatusOK, "borrow "+c.GetParam("book_id"))
+	})
+
+	// collection actions are not registered beside the member parameter
+	ctx := &fasthttp.RequestCtx{}
+	h, _ := g.Router.Lookup("POST", "/books/import", ctx)
+	as.NotNil(h)
+	as.Equal("import", ctx.UserValue("book_id"))
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/books").Expect().Status(http.StatusOK).Body().Equal("books")
+	e.GET("/books/search").Expect().Status(http.StatusOK).Body().Equal("search")
+	e.POST("/books/import").Expect().Status(http.StatusOK).Body().Equal("import")
+	e.GET("/books/3/reviews").Expect().Status(http.StatusOK).Body().Equal("reviews of 3")
+	e.POST("/books/3/borrow").Expect().Status(http.StatusOK).Body().Equal("borrow 3")
+	e.POST("/books/3").Expect().Status(http.StatusNotFound)
+	e.OPTIONS("/books/3").Expect().Status(http.StatusNotFound)
+	e.OPTIONS("/books/import").Expect().Header("Allow").Equal("POST, OPTIONS")
+}
+
+func TestResource_CollectionMethodNotAllowed(t *testing.T) {
+	g := New("testfiles/config_test.yaml")
+
+	// POST has no member route, but it's dispatched on the member path for import
+	users := g.Router.Resource("/users", &userResource{})
+	users.Collection("POST", "import", func(c *Context) error {
+		return c.STRING(http.StatusOK, "import")
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.POST("/users/import").Expect().Status(http.StatusOK).Body().Equal("import")
+	e.OPTIONS("/users/5").Expect().Header("Allow").Equal("GET, PUT, PATCH, DELETE, OPTIONS")
+
+	ee := e.POST("/users/5").Expect()
+	ee.Status(http.StatusMethodNotAllowed)
+	ee.Header("Allow").Equal("GET, PUT, PATCH, DELETE, OPTIONS")
+}
+
+func TestRouterGroup_Resource(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+
+	res := g.Router.Group("/api", testGroupMiddleware("api")).Resource("/categories", &userResource{})
+	as.Equal("/api/categories", res.Path())
+	as.Equal("category_id", res.Param())
+
+	u, _ := g.Router.URL("api.categories.show", 3)
+	as.Equal("/api/categories/3", u)
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/api/categories/3").Expect().Status(http.StatusOK).Header("X-Group").Equal("api")
+	e.GET("/api/categories/new").Expect().Status(http.StatusOK).Body().Equal("new")
+}
+
+func TestSingular(t *testing.T) {
+	as := assert.New(t)
+
+	for plural, s := range map[string]string{
+		"users":      "user",
+		"categories": "category",
+		"boxes":      "box",
+		"matches":    "match",
+		"addresses":  "address",
+		"class":      "class",
+		"data":       "data",
+	} {
+		as.Equal(s, singular(plural), plural)
+	}
+}
@@ -1,15 +1,20 @@
 package gas
 
 import (
+	"net/http"
 	"reflect"
 	"strings"
 
 	"github.com/buaazp/fasthttprouter"
+	"github.com/fasthttp/websocket"
 	"github.com/valyala/fasthttp"
 )
 
 var supportRestProto = [7]string{"GET", "POST", "DELETE", "HEAD", "OPTIONS", "PUT", "PATCH"}
 
+// allowMethods are checked in order to build the Allow header, followed by other registered methods
+var allowMethods = [6]string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}
+
 type (
 
 	// Router class include httprouter and gas
@@ -17,6 +22,54 @@ type (
 		*fasthttprouter.Router
 		g           *Engine
 		middlewares []GasMiddlewareFunc
+
+		// names of middlewares for Routes
+		middlewareNames []string
+
+		// registered routes, their handler chains are rebuilt when middleware changed
+		routes           []*Route
+		notFound         *Route
+		methodNotAllowed *Route
+		options          *Route
+
+		// named routes for URL generation
+		names map[string]*Route
+
+		// member dispatchers of resources, methods they don't serve for a path are not allowed
+		dispatchers []*memberDispatcher
+
+		// routers of Engine.Host
+		hosts []*hostRouter
+
+		// parent is g.Router for routers of Engine.Host, hostPattern is their pattern
+		parent      *Router
+		hostPattern string
+
+		errorHandler ErrorHandler
+		panicHandler PanicHandler
+
+		// WebSocketUpgrader upgrades requests of WebSocket routes,
+		// set CheckOrigin to accept cross-origin requests.
+		// It's nil for routers of Engine.Host, which use the one of g.Router then.
+		WebSocketUpgrader *websocket.FastHTTPUpgrader
+	}
+
+	// Route is a registered route, it keeps what a handler is registered with,
+	// so that its chain can be rebuilt. Use Name to generate its URL by Router.URL.
+	Route struct {
+		router      *Router
+		name        string
+		method      string
+		path        string
+		handler     GasHandler
+		middlewares []interface{}
+		group       *RouterGroup
+
+		// handlerName is reported by Routes, it's the function name of handler if empty
+		handlerName string
+
+		// handler chained with global, group and route middlewares
+		chain GasHandler
 	}
 
 	// MiddlewareFunc middlewarefunc define
@@ -27,6 +80,9 @@ type (
 
 	// PanicHandler defined panic handler
 	PanicHandler func(*Context, interface{}) error
+
+	// ErrorHandler handles errors returned by handlers and middlewares
+	ErrorHandler func(*Context, error)
 )
 
 func newRouter(g *Engine) *Router {
@@ -34,6 +90,14 @@ func newRouter(g *Engine) *Router {
 	r := &Router{}
 	r.Router = fastR
 	r.g = g
+	r.names = make(map[string]*Route)
+	r.WebSocketUpgrader = newDefaultUpgrader()
+
+	// OPTIONS requests fall through to NotFound, so that they are answered through middlewares
+	r.Router.HandleOPTIONS = false
+	r.options = &Route{handler: optionsHandler}
+	r.options.chain = r.compile(r.options)
+	r.NotFound = r.handleNotFound
 
 	return r
 }
@@ -67,111 +131,227 @@ func newRouter(g *Engine) *Router {
 //	}
 //}
 
-func (r *Router) wrapGasHandlerToFasthttpRouterHandler(h GasHandler) fasthttp.RequestHandler {
-	// type Handle func(*fasthttp.RequestCtx, Params)
-	return func(ctx *fasthttp.RequestCtx) {
-		gasCtx := r.g.pool.Get().(*Context)
-		gasCtx.reset(ctx, r.g)
+// serve runs h with a pooled Context
+func (r *Router) serve(ctx *fasthttp.RequestCtx, h GasHandler) {
+	gasCtx := r.g.pool.Get().(*Context)
+	gasCtx.reset(ctx, r.g)
 
-		// chain middleware functions
-		var cpch GasHandler // copy handle avoid repeat chain
-		cpch = h
+	// the Context is released even if h panics
+	defer r.release(gasCtx)
 
-		for i := len(r.middlewares) - 1; i >= 0; i-- {
-			cpch = r.middlewares[i](cpch)
-		}
+	if err := h(gasCtx); err != nil {
+		r.errorHandler(gasCtx, err)
+	}
+}
 
-		if err := cpch(gasCtx); err != nil {
-			// handle error
-			r.PanicHandler(gasCtx.RequestCtx, err)
+// release recovers panics of the handler by the panic handler with the same Context,
+// then closes resources of c, cancels the context of StdContext and puts c back to pool.
+func (r *Router) release(c *Context) {
+	rcv := recover()
+	if rcv != nil && r.panicHandler != nil {
+		if err := r.panicHandler(c, rcv); err != nil {
+			r.errorHandler(c, err)
 		}
+	}
+
+	if c.isUseDB {
+		c.CloseDB()
+	}
+
+	if c.isUseSession {
+		c.SessionEnd()
+	}
+
+	c.finish()
+	r.g.pool.Put(c)
+
+	if rcv != nil && r.panicHandler == nil {
+		panic(rcv)
+	}
+}
+
+// compile chains route's handler with route, group and global middlewares,
+// global ones run first, and those of the parent router before them.
+func (r *Router) compile(rt *Route) GasHandler {
+	h := r.chainMiddleware(rt.handler, rt.middlewares...)
+
+	if rt.group != nil {
+		h = r.chainMiddleware(h, rt.group.allMiddlewares()...)
+	}
+
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		h = r.middlewares[i](h)
+	}
 
-		if gasCtx.isUseDB {
-			defer gasCtx.CloseDB()
+	if r.parent != nil {
+		for i := len(r.parent.middlewares) - 1; i >= 0; i-- {
+			h = r.parent.middlewares[i](h)
 		}
+	}
+
+	return h
+}
 
-		if gasCtx.isUseSession {
-			defer gasCtx.SessionEnd()
+// rebuild compiles handler chains of all routes again, it's called after middlewares changed.
+func (r *Router) rebuild() {
+	for _, rt := range r.routes {
+		// files of StaticPath are served without middlewares
+		if rt.handler == nil {
+			continue
 		}
+		rt.chain = r.compile(rt)
+	}
 
-		// ctx.handlerFunc = ch
-		// ctx.Next()
+	for _, rt := range []*Route{r.notFound, r.methodNotAllowed, r.options} {
+		if rt != nil {
+			rt.chain = r.compile(rt)
+		}
+	}
 
-		r.g.pool.Put(gasCtx)
+	// global middlewares of r are run by routers of hosts too
+	for _, h := range r.hosts {
+		h.router.rebuild()
 	}
 }
 
 // SetNotFoundHandler  set Notfound and Panic handler
 func (r *Router) SetNotFoundHandler(h GasHandler) {
-	r.NotFound = func(fctx *fasthttp.RequestCtx) {
-		ctx := r.g.pool.Get().(*Context) //createContext(rw, req)
-		ctx.reset(fctx, r.g)
+	rt := &Route{handler: h}
+	rt.chain = r.compile(rt)
+	r.notFound = rt
+}
+
+// SetMethodNotAllowedHandler sets the handler for requests whose path matches routes of other methods,
+// the Allow header is set before it's called and global middlewares are run.
+func (r *Router) SetMethodNotAllowedHandler(h GasHandler) {
+	rt := &Route{handler: h}
+	rt.chain = r.compile(rt)
+	r.methodNotAllowed = rt
+	r.MethodNotAllowed = r.handleMethodNotAllowed
+}
+
+func (r *Router) handleMethodNotAllowed(fctx *fasthttp.RequestCtx) {
+	fctx.Response.Header.Set("Allow", r.allowed(string(fctx.Path())))
+	r.serve(fctx, r.methodNotAllowed.chain)
+}
 
-		// chain middleware functions
-		var cpch GasHandler // copy handle avoid repeat chain
-		cpch = h
+// handleNotFound answers OPTIONS requests of registered paths with the Allow header,
+// other methods of paths registered only for OPTIONS are not allowed,
+// other requests are handled by the not found handler.
+func (r *Router) handleNotFound(fctx *fasthttp.RequestCtx) {
+	if allow := r.allowed(string(fctx.Path())); allow != "" {
+		if fctx.IsOptions() {
+			fctx.Response.Header.Set("Allow", allow)
+			r.serve(fctx, r.options.chain)
+			return
+		}
 
-		for i := len(r.middlewares) - 1; i >= 0; i-- {
-			cpch = r.middlewares[i](cpch)
+		if r.methodNotAllowed != nil {
+			r.handleMethodNotAllowed(fctx)
+			return
 		}
+	}
 
-		if err := cpch(ctx); err != nil {
+	if r.notFound != nil {
+		r.serve(fctx, r.notFound.chain)
+		return
+	}
+
+	fctx.Error(fasthttp.StatusMessage(fasthttp.StatusNotFound), fasthttp.StatusNotFound)
+}
 
+// allowed returns methods of routes matching path joined for the Allow header,
+// OPTIONS is included if any of them matches.
+func (r *Router) allowed(path string) string {
+	methods := allowMethods[:]
+	for _, rt := range r.routes {
+		if !containsMethod(methods, rt.method) {
+			methods = append(methods, rt.method)
 		}
+	}
 
-		r.g.pool.Put(ctx)
+	var allow []string
+	for _, m := range methods {
+		if h, _ := r.Lookup(m, path, nil); h != nil && r.dispatched(m, path) {
+			allow = append(allow, m)
+		}
 	}
-	//r.NotFound = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-	//	ctx := r.g.pool.Get().(*Context) //createContext(rw, req)
-	//	ctx.reset(w, req, r.g)
-	//
-	//	// chain middleware functions
-	//	var cpch GasHandler // copy handle avoid repeat chain
-	//	cpch = h
-	//
-	//	for i := len(r.middlewares) - 1; i >= 0; i-- {
-	//		cpch = r.middlewares[i](cpch)
-	//	}
-	//
-	//	if err := cpch(ctx); err != nil {
-	//
-	//	}
-	//
-	//	r.g.pool.Put(ctx)
-	//})
+
+	if len(allow) == 0 {
+		return ""
+	}
+
+	if !containsMethod(allow, "OPTIONS") {
+		allow = append(allow, "OPTIONS")
+	}
+
+	return strings.Join(allow, ", ")
+}
+
+// containsMethod reports whether methods contain method
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dispatched reports whether member dispatchers of method serve path,
+// it's true if path doesn't match any of them.
+func (r *Router) dispatched(method, path string) bool {
+	for _, d := range r.dispatchers {
+		if d.method != method {
+			continue
+		}
+
+		if matched, served := d.serves(path); matched {
+			return served
+		}
+	}
+
+	return true
 }
 
+// optionsHandler answers OPTIONS requests, the Allow header is set before
+func optionsHandler(c *Context) error {
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SetErrorHandler sets the handler for errors returned by route, not found handler and middlewares,
+// panics are still handled by PanicHandler.
+func (r *Router) SetErrorHandler(h ErrorHandler) {
+	r.errorHandler = h
+}
+
+// SetPanicHandler sets the handler for panics of handlers and middlewares,
+// it's called with the Context of the request.
 func (r *Router) SetPanicHandler(ph PanicHandler) {
+	r.panicHandler = ph
+
+	// panics outside of gas handlers, Ex: files of StaticPath
 	r.PanicHandler = func(fctx *fasthttp.RequestCtx, rcv interface{}) {
-		ctx := r.g.pool.Get().(*Context) //createContext(rw, req)
+		ctx := r.g.pool.Get().(*Context)
 		ctx.reset(fctx, r.g)
 
 		if err := ph(ctx, rcv); err != nil {
-
+			r.errorHandler(ctx, err)
 		}
 
+		ctx.finish()
 		r.g.pool.Put(ctx)
 	}
-	//r.hr.PanicHandler = func(w http.ResponseWriter, req *http.Request, rcv interface{}) {
-	//	// c := a.createContext(w, req)
-	//	// a.panicFunc(c, rcv)
-	//	// a.pool.Put(c)
-	//
-	//	ctx := r.g.pool.Get().(*Context) //createContext(rw, req)
-	//	ctx.reset(w, req, r.g)
-	//
-	//	if err := ph(ctx, rcv); err != nil {
-	//
-	//	}
-	//
-	//	r.g.pool.Put(ctx)
-	//}
 }
 
+// Use adds global middleware, it works for all routes including those registered before.
 func (r *Router) Use(m interface{}) {
+	r.middlewareNames = append(r.middlewareNames, funcName(m))
 	m = wrapMiddleware(m)
 
 	r.middlewares = append(r.middlewares, m.(GasMiddlewareFunc))
+	r.rebuild()
 }
 
 // wrapMiddleware wraps middleware.
@@ -203,10 +383,22 @@ func wrapHandlerFuncToMiddlewareFunc(m GasHandler) GasMiddlewareFunc {
 	}
 }
 
-func (r *Router) setRoute(method, path string, ch GasHandler) {
-	//r.hr.Handle(method, path, func(rw http.ResponseWriter, req *http.Request, ps httprouter.Params) {
-	h := r.wrapGasHandlerToFasthttpRouterHandler(ch)
-	r.Handle(method, path, h)
+// addRoute compiles route's chain and registers it to fasthttprouter
+func (r *Router) addRoute(rt *Route) *Route {
+	r.register(rt)
+
+	r.Handle(rt.method, rt.path, func(ctx *fasthttp.RequestCtx) {
+		r.serve(ctx, rt.chain)
+	})
+
+	return rt
+}
+
+// register compiles route's chain and records it, the caller handles its path.
+func (r *Router) register(rt *Route) {
+	rt.router = r
+	rt.chain = r.compile(rt)
+	r.routes = append(r.routes, rt)
 }
 
 //func checkHandler(h interface{}) GasHandler {
@@ -232,47 +424,48 @@ func (r *Router) chainMiddleware(h GasHandler, middlewares ...interface{}) GasHa
 	return res
 }
 
-func (r *Router) set(method, path string, ch GasHandler, middlewares ...interface{}) {
-	if len(middlewares) != 0 {
-		ch = r.chainMiddleware(ch, middlewares...)
-	}
-
-	r.setRoute(method, path, ch)
+func (r *Router) set(method, path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return r.addRoute(&Route{
+		method:      method,
+		path:        path,
+		handler:     ch,
+		middlewares: middlewares,
+	})
 }
 
 // Get REST funcs
-func (r *Router) Get(path string, ch GasHandler, middlewares ...interface{}) {
-	r.set("GET", path, ch, middlewares...)
+func (r *Router) Get(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return r.set("GET", path, ch, middlewares...)
 }
 
 // Post REST funcs
-func (r *Router) Post(path string, ch GasHandler, middlewares ...interface{}) {
-	r.set("POST", path, ch, middlewares...)
+func (r *Router) Post(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return r.set("POST", path, ch, middlewares...)
 }
 
 // Delete REST funcs
-func (r *Router) Delete(path string, ch GasHandler, middlewares ...interface{}) {
-	r.set("DELETE", path, ch, middlewares...)
+func (r *Router) Delete(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return r.set("DELETE", path, ch, middlewares...)
 }
 
 // Head REST funcs
-func (r *Router) Head(path string, ch GasHandler, middlewares ...interface{}) {
-	r.set("HEAD", path, ch, middlewares...)
+func (r *Router) Head(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return r.set("HEAD", path, ch, middlewares...)
 }
 
 // Options REST funcs
-func (r *Router) Options(path string, ch GasHandler, middlewares ...interface{}) {
-	r.set("OPTIONS", path, ch, middlewares...)
+func (r *Router) Options(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return r.set("OPTIONS", path, ch, middlewares...)
 }
 
 // Put REST funcs
-func (r *Router) Put(path string, ch GasHandler, middlewares ...interface{}) {
-	r.set("PUT", path, ch, middlewares...)
+func (r *Router) Put(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return r.set("PUT", path, ch, middlewares...)
 }
 
 // Patch REST funcs
-func (r *Router) Patch(path string, ch GasHandler, middlewares ...interface{}) {
-	r.set("PATCH", path, ch, middlewares...)
+func (r *Router) Patch(path string, ch GasHandler, middlewares ...interface{}) *Route {
+	return r.set("PATCH", path, ch, middlewares...)
 }
 
 func (r *Router) StaticPath(dir string) {
@@ -288,6 +481,19 @@ func (r *Router) StaticPath(dir string) {
 	//r.Router.ServeFiles("/"+dir+"/*filepath", dir)
 
 	path := "/" + dir + "/*filepath"
+
+	// recorded for Routes only
+	r.routes = append(r.routes, &Route{
+		router:      r,
+		method:      "GET",
+		path:        path,
+		handlerName: "StaticPath(" + dir + ")",
+	})
+	r.GET(path, newStaticHandler(path, dir))
+}
+
+// newStaticHandler creates a file server handler serving dir on path which ends with /*filepath.
+func newStaticHandler(path, dir string) fasthttp.RequestHandler {
 	//absFilePath, _ := filepath.Abs(dir)
 
 	//println(absFilePath)
@@ -305,20 +511,35 @@ func (r *Router) StaticPath(dir string) {
 
 	fsHandler := fs.NewRequestHandler()
 
-	r.GET(path, func(ctx *fasthttp.RequestCtx) {
+	return func(ctx *fasthttp.RequestCtx) {
 		fsHandler(ctx)
-	})
+	}
 }
 
-// REST for set all REST route
+// REST for set all REST route, methods named by HTTP methods (Get, Post...) are called
+// on a new controller for each request, see ControllerFactory and Preparer.
 func (r *Router) REST(path string, c ControllerInterface) {
+	r.rest(nil, path, c)
+}
+
+func (r *Router) rest(rg *RouterGroup, path string, c ControllerInterface) {
+	ct := newControllerType(c)
+	mws := ct.middlewares()
+
 	// get all functions in controller
-	refT := reflect.TypeOf(c)
+	refT := reflect.TypeOf(ct.sample())
 	for i := 0; i < refT.NumMethod(); i++ {
 		m := refT.Method(i)
 		if checkSupportProto(m.Name) {
-			revf := reflect.ValueOf(c)
-			r.set(strings.ToUpper(m.Name), path, revf.MethodByName(m.Name).Interface().(func(*Context) error))
+			h, name := ct.action(m.Name)
+			r.addRoute(&Route{
+				method:      strings.ToUpper(m.Name),
+				path:        path,
+				handler:     h,
+				middlewares: mws,
+				group:       rg,
+				handlerName: name,
+			})
 		}
 
 	}
@@ -1,6 +1,10 @@
 package gas
 
 import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
 	"net/http"
 	"testing"
 )
@@ -97,6 +101,58 @@ func TestRouter_Options(t *testing.T) {
 	ee.Body().Equal("Option")
 }
 
+func TestRouter_AutoOptions(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml")
+	g.Router.Use(testGroupMiddleware("global"))
+
+	g.Router.Get("/users/:id", indexPage)
+	g.Router.Delete("/users/:id", indexPage)
+	g.Router.Put("/users/:id", indexPage)
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	ee := e.OPTIONS("/users/5").Expect()
+	ee.Status(http.StatusNoContent)
+	ee.Header("Allow").Equal("GET, PUT, DELETE, OPTIONS")
+	ee.Header("X-Group").Equal("global")
+
+	e.OPTIONS("/none").Expect().Status(http.StatusNotFound)
+}
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	g.Router.Get("/users/:id", indexPage)
+	g.Router.Patch("/users/:id", indexPage)
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	ee := e.POST("/users/5").Expect()
+	ee.Status(http.StatusMethodNotAllowed)
+	ee.Header("Allow").Equal("GET, PATCH, OPTIONS")
+	ee.Body().Equal("405 method not allowed.")
+
+	// middlewares added later are run too
+	g.Router.SetMethodNotAllowedHandler(func(c *Context) error {
+		return NewHTTPError(http.StatusMethodNotAllowed, "use "+string(c.Response.Header.Peek("Allow")))
+	})
+	g.Router.Use(testGroupMiddleware("global"))
+
+	ee = e.DELETE("/users/5").Expect()
+	ee.Status(http.StatusMethodNotAllowed)
+	ee.Header("X-Group").Equal("global")
+	ee.Body().Contains("use GET, PATCH, OPTIONS")
+
+	// explicitly registered OPTIONS routes are allowed too
+	g.Router.Options("/ping", indexPage)
+
+	ee = e.GET("/ping").Expect()
+	ee.Status(http.StatusMethodNotAllowed)
+	ee.Body().Contains("use OPTIONS")
+}
+
 func TestRouter_Head(t *testing.T) {
 	// new gas
 	g := New("testfiles/config_test.yaml")
@@ -193,3 +249,265 @@ func TestRouter_SetGasHandlerAsMiddleware(t *testing.T) {
 	e.GET("/test").WithFormField("Test", "DontGo").
 		Expect().Status(http.StatusForbidden).Body().Equal("ERROR-NO")
 }
+
+func testGroupMiddleware(name string) GasMiddlewareFunc {
+	return func(next GasHandler) GasHandler {
+		return func(ctx *Context) error {
+			ctx.Response.Header.Add("X-Group", name)
+			return next(ctx)
+		}
+	}
+}
+
+func TestRouter_Group(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	api := g.Router.Group("/api/v1/", testGroupMiddleware("api"))
+	api.Get("/", func(c *Context) error {
+		return c.STRING(http.StatusOK, "api index")
+	})
+	api.Get("/users/:id", func(c *Context) error {
+		return c.STRING(http.StatusOK, "user "+c.GetParam("id"))
+	})
+	api.Post("/users", func(c *Context) error {
+		return c.STRING(http.StatusOK, "created "+c.GetParam("Test"))
+	}, testMiddleware1)
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	ee := e.GET("/api/v1").Expect()
+	ee.Status(http.StatusOK)
+	ee.Header("X-Group").Equal("api")
+	ee.Body().Equal("api index")
+
+	ee = e.GET("/api/v1/users/10").Expect()
+	ee.Status(http.StatusOK)
+	ee.Header("X-Group").Equal("api")
+	ee.Body().Equal("user 10")
+
+	// group middleware runs before route middleware
+	ee = e.POST("/api/v1/users").WithFormField("Test", "Go").Expect()
+	ee.Status(http.StatusOK)
+	ee.Header("X-Group").Equal("api")
+	ee.Body().Equal("created Go")
+
+	ee = e.POST("/api/v1/users").WithFormField("Test", "DontGo").Expect()
+	ee.Status(http.StatusForbidden)
+	ee.Header("X-Group").Equal("api")
+	ee.Body().Equal("ERROR")
+}
+
+func TestRouter_NestedGroup(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	api := g.Router.Group("/api")
+	api.Use(testGroupMiddleware("api"))
+
+	admin := api.Group("/admin", testGroupMiddleware("admin"))
+	admin.Use(testGroupMiddleware("admin-use"))
+	admin.Delete("/users/:id", func(c *Context) error {
+		return c.STRING(http.StatusOK, "deleted "+c.GetParam("id"))
+	})
+	admin.REST("/rest", &testController{})
+	admin.StaticPath("testfiles")
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	ee := e.DELETE("/api/admin/users/3").Expect()
+	ee.Status(http.StatusOK)
+	ee.Body().Equal("deleted 3")
+	assert.Equal(t, []string{"api", "admin", "admin-use"}, ee.Raw().Header["X-Group"])
+
+	ee = e.GET("/api/admin/rest").Expect()
+	ee.Status(http.StatusOK)
+	ee.Body().Equal("Get Test")
+	assert.Equal(t, []string{"api", "admin", "admin-use"}, ee.Raw().Header["X-Group"])
+
+	ee = e.GET("/api/admin/testfiles/static.txt").Expect()
+	ee.Status(http.StatusOK)
+	ee.Body().Equal("This is a static file")
+	assert.Equal(t, []string{"api", "admin", "admin-use"}, ee.Raw().Header["X-Group"])
+
+	e.GET("/admin/users/3").Expect().Status(http.StatusNotFound)
+}
+
+func TestRouter_GroupPrefix(t *testing.T) {
+	as := assert.New(t)
+
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	api := g.Router.Group("api")
+	as.Equal("/api", api.Prefix())
+	as.Equal("/api/v1", api.Group("v1/").Prefix())
+	as.Equal("/api/admin", api.Group("//admin").Prefix())
+	as.Equal("", g.Router.Group("/").Prefix())
+
+	api.Group("v1").Get("users", func(c *Context) error {
+		return c.STRING(http.StatusOK, "users")
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+	e.GET("/api/v1/users").Expect().Status(http.StatusOK).Body().Equal("users")
+}
+
+func TestRouter_UseAfterRoutes(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	g.Router.Get("/test", func(c *Context) error {
+		return c.STRING(http.StatusOK, "TEST")
+	})
+
+	api := g.Router.Group("/api")
+	api.Get("/test", func(c *Context) error {
+		return c.STRING(http.StatusOK, "API")
+	})
+
+	// middlewares added after routes work for them and not found handler
+	g.Router.Use(testGroupMiddleware("global"))
+	api.Use(testGroupMiddleware("api"))
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	ee := e.GET("/test").Expect()
+	ee.Status(http.StatusOK).Body().Equal("TEST")
+	assert.Equal(t, []string{"global"}, ee.Raw().Header["X-Group"])
+
+	ee = e.GET("/api/test").Expect()
+	ee.Status(http.StatusOK).Body().Equal("API")
+	assert.Equal(t, []string{"global", "api"}, ee.Raw().Header["X-Group"])
+
+	ee = e.GET("/none").Expect()
+	ee.Status(http.StatusNotFound)
+	assert.Equal(t, []string{"global"}, ee.Raw().Header["X-Group"])
+}
+
+func TestRouter_DefaultErrorHandler(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	g.Router.Get("/forbidden", func(c *Context) error {
+		return NewHTTPError(http.StatusForbidden, "no permission")
+	})
+	g.Router.Get("/error", func(c *Context) error {
+		c.STRING(http.StatusOK, "partial")
+		return errors.New("db is down")
+	})
+	g.Router.Get("/wrapped", func(c *Context) error {
+		return fmt.Errorf("load user: %w", NewHTTPError(http.StatusNotFound, "no such user"))
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/forbidden").WithHeader("Accept", "application/json").Expect().
+		Status(http.StatusForbidden).
+		ContentType("application/json").
+		JSON().Equal(H{"code": http.StatusForbidden, "message": "no permission"})
+
+	ee := e.GET("/forbidden").WithHeader("Accept", "text/html,application/json;q=0.9").Expect()
+	ee.Status(http.StatusForbidden).ContentType("text/html")
+	ee.Body().Contains("403 Forbidden").Contains("no permission")
+
+	// q-values are respected, not the position in Accept header
+	e.GET("/forbidden").WithHeader("Accept", "application/json;q=0.1, text/html").Expect().
+		Status(http.StatusForbidden).ContentType("text/html")
+	e.GET("/forbidden").WithHeader("Accept", "text/html;q=0.5, application/json").Expect().
+		Status(http.StatusForbidden).ContentType("application/json")
+
+	// HTTPError wrapped by other errors
+	e.GET("/wrapped").WithHeader("Accept", "application/json").Expect().
+		Status(http.StatusNotFound).
+		JSON().Equal(H{"code": http.StatusNotFound, "message": "no such user"})
+
+	// internal error is hidden when not in DEV mode
+	ee = e.GET("/error").Expect()
+	ee.Status(http.StatusInternalServerError).ContentType("text/html")
+	ee.Body().Contains("Internal Server Error").NotContains("db is down").NotContains("partial")
+
+	g.Config.Load("testfiles/config_dev.yaml")
+	e.GET("/error").WithHeader("Accept", "application/json").Expect().
+		Status(http.StatusInternalServerError).
+		JSON().Equal(H{"code": http.StatusInternalServerError, "message": "db is down"})
+}
+
+func TestRouter_SetErrorHandler(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml")
+
+	g.Router.SetErrorHandler(func(c *Context, err error) {
+		c.STRING(http.StatusTeapot, "handled: "+err.Error())
+	})
+	g.Router.SetNotFoundHandler(func(c *Context) error {
+		return errors.New("not found")
+	})
+	g.Router.Get("/", func(c *Context) error {
+		return errors.New("route")
+	}, func(c *Context) error {
+		if string(c.QueryArgs().Peek("Test")) == "middleware" {
+			return errors.New("middleware")
+		}
+
+		return nil
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/").Expect().Status(http.StatusTeapot).Body().Equal("handled: route")
+	e.GET("/").WithQuery("Test", "middleware").Expect().Status(http.StatusTeapot).Body().Equal("handled: middleware")
+	e.GET("/none").Expect().Status(http.StatusTeapot).Body().Equal("handled: not found")
+}
+
+func benchmarkPassMiddleware(next GasHandler) GasHandler {
+	return func(ctx *Context) error {
+		return next(ctx)
+	}
+}
+
+func benchmarkRoute(b *testing.B, path string, setup func(g *Engine)) {
+	b.ReportAllocs()
+
+	// new gas
+	g := New("testfiles/config_test.yaml")
+	setup(g)
+
+	req := fasthttp.Request{}
+	req.SetRequestURI(path)
+	req.Header.SetMethod("GET")
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&req, nil, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx.Response.Reset()
+		g.Router.Handler(ctx)
+	}
+}
+
+func BenchmarkRouter_GlobalMiddlewares(b *testing.B) {
+	benchmarkRoute(b, "/", func(g *Engine) {
+		for i := 0; i < 5; i++ {
+			g.Router.Use(benchmarkPassMiddleware)
+		}
+
+		g.Router.Get("/", indexPage)
+	})
+}
+
+func BenchmarkRouter_RouteMiddlewares(b *testing.B) {
+	benchmarkRoute(b, "/", func(g *Engine) {
+		g.Router.Use(benchmarkPassMiddleware)
+		g.Router.Get("/", indexPage, benchmarkPassMiddleware, benchmarkPassMiddleware)
+	})
+}
+
+func BenchmarkRouter_NotFound(b *testing.B) {
+	benchmarkRoute(b, "/not-found", func(g *Engine) {
+		for i := 0; i < 5; i++ {
+			g.Router.Use(benchmarkPassMiddleware)
+		}
+	})
+}
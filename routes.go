@@ -0,0 +1,149 @@
+package gas
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+)
+
+// RouteInfo describes a registered route
+type RouteInfo struct {
+	// Host is the pattern of Engine.Host, empty for routes of g.Router
+	Host    string `json:"host,omitempty"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Name    string `json:"name,omitempty"`
+	Handler string `json:"handler"`
+
+	// Middlewares are in the order they run, global ones first
+	Middlewares []string `json:"middlewares,omitempty"`
+}
+
+// funcName returns the full name of function f, or its type if f is not a function
+func funcName(f interface{}) string {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func {
+		return v.Type().String()
+	}
+
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return v.Type().String()
+	}
+
+	// method values are named like "pkg.(*T).Get-fm"
+	return strings.TrimSuffix(fn.Name(), "-fm")
+}
+
+// info returns RouteInfo of the route
+func (rt *Route) info() RouteInfo {
+	ri := RouteInfo{
+		Host:    rt.router.hostPattern,
+		Method:  rt.method,
+		Path:    rt.path,
+		Name:    rt.name,
+		Handler: rt.handlerName,
+	}
+	if ri.Handler == "" {
+		ri.Handler = funcName(rt.handler)
+	}
+
+	// files of Router.StaticPath are served without middlewares
+	if rt.handler == nil {
+		return ri
+	}
+
+	if rt.router.parent != nil {
+		ri.Middlewares = append(ri.Middlewares, rt.router.parent.middlewareNames...)
+	}
+	ri.Middlewares = append(ri.Middlewares, rt.router.middlewareNames...)
+	if rt.group != nil {
+		ri.Middlewares = append(ri.Middlewares, rt.group.allMiddlewareNames()...)
+	}
+	for _, m := range rt.middlewares {
+		ri.Middlewares = append(ri.Middlewares, funcName(m))
+	}
+
+	return ri
+}
+
+// allMiddlewareNames returns names of allMiddlewares
+func (rg *RouterGroup) allMiddlewareNames() []string {
+	var res []string
+	if rg.parent != nil {
+		res = rg.parent.allMiddlewareNames()
+	}
+
+	return append(res, rg.middlewareNames...)
+}
+
+// Routes returns all routes registered by set methods, REST, StaticPath and WebSocket
+// in the order they are registered, followed by routes of Engine.Host.
+func (r *Router) Routes() []RouteInfo {
+	res := make([]RouteInfo, 0, len(r.routes))
+	for _, rt := range r.routes {
+		res = append(res, rt.info())
+	}
+
+	for _, h := range r.hosts {
+		res = append(res, h.router.Routes()...)
+	}
+
+	return res
+}
+
+// DebugRoutes serves the route table on path in DEV mode, it responds JSON if
+// the client accepts application/json better than text/plain, otherwise a text table,
+// which has a HOST column if there are routes of Engine.Host.
+// The route is registered only in DEV mode, nil is returned in other modes,
+// so it's safe to keep in production.
+//
+// Ex:
+//
+//	g.Router.DebugRoutes("/_routes")
+//
+//	// curl localhost:8080/_routes
+//	// METHOD  PATH        NAME       HANDLER        MIDDLEWARES
+//	// GET     /users/:id  user.show  main.ShowUser  main.Auth
+func (r *Router) DebugRoutes(path string, middlewares ...interface{}) *Route {
+	if r.g.Config.Get("Mode") != "DEV" {
+		return nil
+	}
+
+	rt := r.set("GET", path, func(c *Context) error {
+		routes := r.Routes()
+		if c.NegotiateFormat(TextPlain, ApplicationJSON) == ApplicationJSON {
+			return c.JSON(http.StatusOK, routes)
+		}
+
+		var buf bytes.Buffer
+		w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+		hosts := len(r.hosts) != 0
+		if hosts {
+			w.Write([]byte("HOST\t"))
+		}
+		w.Write([]byte("METHOD\tPATH\tNAME\tHANDLER\tMIDDLEWARES\n"))
+		for _, ri := range routes {
+			if hosts {
+				w.Write([]byte(ri.Host + "\t"))
+			}
+			w.Write([]byte(ri.Method + "\t" + ri.Path + "\t" + ri.Name + "\t" +
+				ri.Handler + "\t" + strings.Join(ri.Middlewares, ", ") + "\n"))
+		}
+		w.Flush()
+
+		// cells are padded even if they are in the last column
+		lines := strings.Split(buf.String(), "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimRight(l, " ")
+		}
+
+		return c.STRING(http.StatusOK, strings.Join(lines, "\n"))
+	}, middlewares...)
+	rt.handlerName = "DebugRoutes"
+
+	return rt
+}
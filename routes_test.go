@@ -0,0 +1,88 @@
+package gas
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestRouter_Routes(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+	g.Router.Use(testMiddleware1)
+
+	g.Router.Get("/", indexPage).Name("index")
+	api := g.Router.Group("/api", testMiddleware2)
+	api.Post("/users", indexPage, testMiddleware1)
+	api.StaticPath("assets")
+	g.Router.REST("/User", &testController{})
+
+	routes := g.Router.Routes()
+
+	// PubDir is served by New without middlewares
+	as.Equal(RouteInfo{
+		Method:  "GET",
+		Path:    "/testfiles/*filepath",
+		Handler: "StaticPath(testfiles)",
+	}, routes[0])
+
+	as.Equal(RouteInfo{
+		Method:      "GET",
+		Path:        "/",
+		Name:        "index",
+		Handler:     "github.com/go-gas/gas.indexPage",
+		Middlewares: []string{"github.com/go-gas/gas.testMiddleware1"},
+	}, routes[1])
+
+	as.Equal(RouteInfo{
+		Method:  "POST",
+		Path:    "/api/users",
+		Handler: "github.com/go-gas/gas.indexPage",
+		Middlewares: []string{
+			"github.com/go-gas/gas.testMiddleware1",
+			"github.com/go-gas/gas.testMiddleware2",
+			"github.com/go-gas/gas.testMiddleware1",
+		},
+	}, routes[2])
+
+	// group middlewares are run before serving files
+	as.Equal("/api/assets/*filepath", routes[3].Path)
+	as.Equal("StaticPath(assets)", routes[3].Handler)
+	as.Len(routes[3].Middlewares, 2)
+
+	as.Contains(routes, RouteInfo{
+		Method:      "GET",
+		Path:        "/User",
+		Handler:     "github.com/go-gas/gas.(*testController).Get",
+		Middlewares: []string{"github.com/go-gas/gas.testMiddleware1"},
+	})
+}
+
+func TestRouter_DebugRoutes(t *testing.T) {
+	// DEV mode by default
+	g := New()
+	g.Router.Get("/users/:id", indexPage).Name("user.show")
+	g.Router.DebugRoutes("/_routes")
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/_routes").Expect().Status(http.StatusOK).
+		Body().Equal("METHOD  PATH               NAME       HANDLER                          MIDDLEWARES\n" +
+		"GET     /public/*filepath             StaticPath(public)\n" +
+		"GET     /users/:id         user.show  github.com/go-gas/gas.indexPage\n" +
+		"GET     /_routes                      DebugRoutes\n")
+
+	e.GET("/_routes").WithHeader("Accept", "application/json").
+		Expect().Status(http.StatusOK).
+		ContentType("application/json").
+		Body().Contains(`{"method":"GET","path":"/users/:id","name":"user.show","handler":"github.com/go-gas/gas.indexPage"}`)
+
+	g = New("testfiles/config_test.yaml")
+	assert.Nil(t, g.Router.DebugRoutes("/_routes"))
+	assert.Empty(t, g.Router.Routes()[1:])
+
+	e = newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/_routes").Expect().Status(http.StatusNotFound)
+}
@@ -0,0 +1,321 @@
+package gas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// errNoListeners is returned by RunAll and Serve if no listener is given
+var errNoListeners = errors.New("gas: no listeners")
+
+type (
+	// LifecycleHook is called when the engine starts or shuts down
+	LifecycleHook func(*Engine) error
+
+	// Listener defines an address served by RunAll,
+	// use HTTP, HTTPS or UNIX to create it.
+	Listener struct {
+		// Network is "tcp4", "tcp", "tcp6" or "unix", default is "tcp4"
+		Network string
+		Addr    string
+
+		// serve HTTPS when both of them are set
+		CertFile string
+		KeyFile  string
+
+		// file mode of the UNIX socket
+		Mode os.FileMode
+	}
+)
+
+// HTTP returns a Listener serving HTTP on the given TCP4 addr.
+func HTTP(addr string) Listener {
+	return Listener{Network: "tcp4", Addr: addr}
+}
+
+// HTTPS returns a Listener serving HTTPS on the given TCP4 addr.
+func HTTPS(addr, certFile, keyFile string) Listener {
+	return Listener{Network: "tcp4", Addr: addr, CertFile: certFile, KeyFile: keyFile}
+}
+
+// UNIX returns a Listener serving HTTP on the given UNIX addr,
+// existing file at addr will be deleted before listening.
+func UNIX(addr string, mode os.FileMode) Listener {
+	return Listener{Network: "unix", Addr: addr, Mode: mode}
+}
+
+func (l Listener) isTLS() bool {
+	return l.CertFile != "" && l.KeyFile != ""
+}
+
+func (l Listener) listen() (net.Listener, error) {
+	network := l.Network
+	if network == "" {
+		network = "tcp4"
+	}
+
+	if network != "unix" {
+		return net.Listen(network, l.Addr)
+	}
+
+	if err := os.Remove(l.Addr); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unexpected error when trying to remove unix socket file %q: %s", l.Addr, err)
+	}
+	ln, err := net.Listen(network, l.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.Chmod(l.Addr, l.Mode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("cannot chmod %#o for %q: %s", l.Mode, l.Addr, err)
+	}
+
+	return ln, nil
+}
+
+// OnStart registers a hook which is called before the server starts listening,
+// if any hook returns an error the server will not be started.
+func (g *Engine) OnStart(h LifecycleHook) {
+	g.onStart = append(g.onStart, h)
+}
+
+// OnShutdown registers a hook which is called after the server stopped serving,
+// it's the place to close db connections, sessions and other resources.
+func (g *Engine) OnShutdown(h LifecycleHook) {
+	g.onShutdown = append(g.onShutdown, h)
+}
+
+// configureServer applies keys set in the Server section of config to g.Server,
+// fields of keys not set are kept.
+func (g *Engine) configureServer() {
+	s := g.Server
+
+	if g.Config.Get("Server.Name") != nil {
+		s.Name = g.Config.GetString("Server.Name")
+	}
+
+	for key, field := range map[string]*int{
+		"Server.Concurrency":        &s.Concurrency,
+		"Server.MaxRequestBodySize": &s.MaxRequestBodySize,
+		"Server.ReadBufferSize":     &s.ReadBufferSize,
+		"Server.WriteBufferSize":    &s.WriteBufferSize,
+		"Server.MaxConnsPerIP":      &s.MaxConnsPerIP,
+		"Server.MaxRequestsPerConn": &s.MaxRequestsPerConn,
+	} {
+		if g.Config.Get(key) != nil {
+			*field = g.configInt(key)
+		}
+	}
+
+	for key, field := range map[string]*time.Duration{
+		"Server.ReadTimeout":  &s.ReadTimeout,
+		"Server.WriteTimeout": &s.WriteTimeout,
+		"Server.IdleTimeout":  &s.IdleTimeout,
+	} {
+		if g.Config.Get(key) != nil {
+			*field = g.configDuration(key, time.Second)
+		}
+	}
+
+	for key, field := range map[string]*bool{
+		"Server.DisableKeepalive":  &s.DisableKeepalive,
+		"Server.ReduceMemoryUsage": &s.ReduceMemoryUsage,
+		"Server.StreamRequestBody": &s.StreamRequestBody,
+	} {
+		if g.Config.Get(key) != nil {
+			*field = g.configBool(key)
+		}
+	}
+}
+
+// serve runs start hooks and then the given listen function with engine's server.
+func (g *Engine) serve(listen func(s *fasthttp.Server) error) error {
+	for _, h := range g.onStart {
+		if err := h(g); err != nil {
+			return err
+		}
+	}
+
+	return listen(g.Server)
+}
+
+// RunAll listens on all of the given listeners and serves them with the same router.
+// It blocks until the server is shut down or any of listeners fails,
+// in that case all of them are shut down together and the first error is returned.
+// An error is returned without calling start hooks if no listener is given.
+//
+// Ex:
+//
+//	g.RunAll(
+//		gas.HTTP(":8080"),
+//		gas.HTTPS(":8443", "server.crt", "server.key"),
+//		gas.UNIX("/tmp/gas.sock", 0666),
+//	)
+func (g *Engine) RunAll(listeners ...Listener) error {
+	lns := make([]net.Listener, 0, len(listeners))
+	for _, l := range listeners {
+		ln, err := l.listen()
+		if err != nil {
+			for _, ln := range lns {
+				ln.Close()
+			}
+
+			return err
+		}
+
+		fmt.Println("Server is Listen on: " + l.Addr)
+		lns = append(lns, ln)
+	}
+
+	return g.serveListeners(lns, listeners)
+}
+
+// Serve serves HTTP requests from all of the given listeners, it works like RunAll.
+func (g *Engine) Serve(lns ...net.Listener) error {
+	return g.serveListeners(lns, make([]Listener, len(lns)))
+}
+
+// serveListeners serves lns with g.Server, specs[i] tells whether lns[i] should serve HTTPS.
+func (g *Engine) serveListeners(lns []net.Listener, specs []Listener) error {
+	if len(lns) == 0 {
+		return errNoListeners
+	}
+
+	return g.serve(func(s *fasthttp.Server) error {
+		errs := make(chan error, len(lns))
+		for i, ln := range lns {
+			go func(ln net.Listener, l Listener) {
+				if l.isTLS() {
+					errs <- s.ServeTLS(ln, l.CertFile, l.KeyFile)
+				} else {
+					errs <- s.Serve(ln)
+				}
+			}(ln, specs[i])
+		}
+
+		// Serve returns nil after shutdown, so the first error means
+		// a listener failed and all of others should be stopped.
+		var err error
+		for range lns {
+			if serr := <-errs; serr != nil && err == nil {
+				err = serr
+
+				s.Shutdown()
+				// in case some of them are not served yet
+				for _, ln := range lns {
+					ln.Close()
+				}
+			}
+		}
+
+		return err
+	})
+}
+
+// Shutdown gracefully shuts down the server, it stops accepting new connections,
+// closes idle keep-alive connections and waits for in-flight requests until
+// they are finished or ctx is done. Then all shutdown hooks will be called.
+//
+// If ctx is done before requests are finished, the hooks are called while their
+// handlers may be still running, so resources used by handlers should be closed safely.
+// Event streams and WebSocket keepalives are ended by Shutdown, so the engine
+// can not be run again after it.
+//
+// Ex:
+//
+//	go g.Run()
+//
+//	quit := make(chan os.Signal, 1)
+//	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+//	<-quit
+//
+//	g.Stop()
+func (g *Engine) Shutdown(ctx context.Context) error {
+	// event streams never finish by themselves
+	g.closingOnce.Do(func() {
+		close(g.closing)
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Server.Shutdown()
+	}()
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	var err error
+wait:
+	for {
+		// keep-alive connections waiting for next request would block shutdown forever
+		g.closeIdleConns()
+
+		select {
+		case err = <-done:
+			break wait
+		case <-ctx.Done():
+			err = ctx.Err()
+			break wait
+		case <-ticker.C:
+		}
+	}
+
+	for _, h := range g.onShutdown {
+		if herr := h(g); herr != nil && err == nil {
+			err = herr
+		}
+	}
+
+	return err
+}
+
+// Stop shuts down the server and waits ShutdownTimeout seconds (read from config)
+// for in-flight requests.
+func (g *Engine) Stop() error {
+	ctx := context.Background()
+	if timeout := g.configDuration("ShutdownTimeout", time.Second); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return g.Shutdown(ctx)
+}
+
+// trackConn records connection states so that idle ones can be closed on shutdown.
+func (g *Engine) trackConn(c net.Conn, state fasthttp.ConnState) {
+	g.connsMu.Lock()
+	defer g.connsMu.Unlock()
+
+	switch state {
+	case fasthttp.StateClosed, fasthttp.StateHijacked:
+		delete(g.conns, c)
+	default:
+		if g.conns == nil {
+			g.conns = make(map[net.Conn]fasthttp.ConnState)
+		}
+		g.conns[c] = state
+	}
+}
+
+// closeIdleConns closes keep-alive connections between requests, new connections
+// are kept because they may be still sending the first request, they are closed
+// by fasthttp after the request or ReadTimeout, so set ReadTimeout to avoid waiting
+// for connections which never send a request.
+func (g *Engine) closeIdleConns() {
+	g.connsMu.Lock()
+	defer g.connsMu.Unlock()
+
+	for c, state := range g.conns {
+		if state == fasthttp.StateIdle {
+			c.Close()
+			delete(g.conns, c)
+		}
+	}
+}
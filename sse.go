@@ -0,0 +1,324 @@
+package gas
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrStreamClosed is returned when sending to a closed EventStream
+var ErrStreamClosed = errors.New("event stream is closed")
+
+// DefaultHeartbeat is the interval of heartbeat comments of EventStream,
+// they keep connections alive through proxies and detect disconnected clients.
+var DefaultHeartbeat = 15 * time.Second
+
+// eventBufferSize is the number of events can be queued in an EventStream
+const eventBufferSize = 16
+
+// Event is a server-sent event
+type Event struct {
+	// ID is the event id, client sends the last one in Last-Event-ID header when reconnecting
+	ID string
+
+	// Event is the event type, client receives "message" if it's empty
+	Event string
+
+	// Data is written as is if it's string or []byte, otherwise it's encoded to json
+	Data interface{}
+
+	// Retry tells client how long to wait before reconnecting
+	Retry time.Duration
+}
+
+// EventStream writes server-sent events to a client, it is created by Context.SSE.
+type EventStream struct {
+	events      chan []byte
+	done        chan struct{}
+	closeOnce   sync.Once
+	lastEventID string
+}
+
+// SSE starts a server-sent events stream, events are written after the handler returns
+// until the stream is closed, the client disconnects or the engine shuts down.
+// Heartbeat comments are sent every DefaultHeartbeat or the given interval.
+//
+// Ex:
+//
+//	func Clock(c *gas.Context) error {
+//		s := c.SSE()
+//		go func() {
+//			ticker := time.NewTicker(time.Second)
+//			defer ticker.Stop()
+//			for {
+//				select {
+//				case t := <-ticker.C:
+//					s.Send(gas.Event{Event: "tick", Data: t.String()})
+//				case <-s.Done():
+//					return
+//				}
+//			}
+//		}()
+//
+//		return nil
+//	}
+func (ctx *Context) SSE(heartbeat ...time.Duration) *EventStream {
+	interval := DefaultHeartbeat
+	if len(heartbeat) != 0 && heartbeat[0] > 0 {
+		interval = heartbeat[0]
+	}
+
+	s := &EventStream{
+		events:      make(chan []byte, eventBufferSize),
+		done:        make(chan struct{}),
+		lastEventID: string(ctx.Request.Header.Peek("Last-Event-ID")),
+	}
+
+	ctx.SetContentType("text/event-stream")
+	ctx.Response.Header.Set("Cache-Control", "no-cache")
+	// disable buffering of nginx
+	ctx.Response.Header.Set("X-Accel-Buffering", "no")
+	ctx.SetStatusCode(200)
+
+	// ctx is reused after handler returns, so only the engine is kept
+	closing := ctx.gas.closing
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		s.run(w, interval, closing)
+	})
+
+	return s
+}
+
+func (s *EventStream) run(w *bufio.Writer, interval time.Duration, closing <-chan struct{}) {
+	defer s.Close()
+
+	// send headers to client immediately
+	w.WriteString(": connected\n\n")
+	if w.Flush() != nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case b := <-s.events:
+			w.Write(b)
+		case <-ticker.C:
+			w.WriteString(": heartbeat\n\n")
+		case <-s.done:
+			// write events sent before closing
+			for {
+				select {
+				case b := <-s.events:
+					w.Write(b)
+				default:
+					w.Flush()
+					return
+				}
+			}
+		case <-closing:
+			return
+		}
+
+		// flush fails if the client is disconnected
+		if w.Flush() != nil {
+			return
+		}
+	}
+}
+
+// Send queues event to be written, it blocks if the queue is full,
+// ErrStreamClosed is returned if the stream is closed.
+func (s *EventStream) Send(e Event) error {
+	b, err := e.encode()
+	if err != nil {
+		return err
+	}
+
+	return s.send(b, true)
+}
+
+func (s *EventStream) send(b []byte, block bool) error {
+	select {
+	case <-s.done:
+		return ErrStreamClosed
+	default:
+	}
+
+	if !block {
+		select {
+		case s.events <- b:
+			return nil
+		default:
+			return errors.New("event stream is full")
+		}
+	}
+
+	select {
+	case s.events <- b:
+		return nil
+	case <-s.done:
+		return ErrStreamClosed
+	}
+}
+
+// Close ends the stream, events sent before are still written.
+func (s *EventStream) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// Done returns a channel which is closed when the stream is closed or the client is disconnected.
+func (s *EventStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// LastEventID returns Last-Event-ID header sent by client when reconnecting
+func (s *EventStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// newlineReplacer removes line breaks from single line fields
+var newlineReplacer = strings.NewReplacer("\r", "", "\n", "")
+
+func (e *Event) encode() ([]byte, error) {
+	var data []byte
+	switch d := e.Data.(type) {
+	case nil:
+	case string:
+		data = []byte(d)
+	case []byte:
+		data = d
+	default:
+		b, err := json.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	}
+
+	var buf bytes.Buffer
+	if e.ID != "" {
+		buf.WriteString("id: " + newlineReplacer.Replace(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		buf.WriteString("event: " + newlineReplacer.Replace(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		buf.WriteString("retry: " + strconv.FormatInt(int64(e.Retry/time.Millisecond), 10) + "\n")
+	}
+
+	data = bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+// Broker broadcasts events to streams subscribed to topics,
+// streams are unsubscribed when they are closed.
+//
+// Ex:
+//
+//	var news = gas.NewBroker()
+//
+//	// GET /news/events
+//	func NewsEvents(c *gas.Context) error {
+//		news.Subscribe("news", c.SSE())
+//		return nil
+//	}
+//
+//	// POST /news
+//	func PostNews(c *gas.Context) error {
+//		...
+//		news.Publish("news", gas.Event{Event: "created", Data: n})
+//		return c.NoContent(http.StatusCreated)
+//	}
+type Broker struct {
+	mu     sync.RWMutex
+	topics map[string]map[*EventStream]struct{}
+}
+
+// NewBroker creates a broker
+func NewBroker() *Broker {
+	return &Broker{
+		topics: make(map[string]map[*EventStream]struct{}),
+	}
+}
+
+// Subscribe adds stream to topic
+func (b *Broker) Subscribe(topic string, s *EventStream) {
+	b.mu.Lock()
+	subs, ok := b.topics[topic]
+	if !ok {
+		subs = make(map[*EventStream]struct{})
+		b.topics[topic] = subs
+	}
+	subs[s] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-s.Done()
+		b.Unsubscribe(topic, s)
+	}()
+}
+
+// Unsubscribe removes stream from topic
+func (b *Broker) Unsubscribe(topic string, s *EventStream) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs, ok := b.topics[topic]
+	if !ok {
+		return
+	}
+
+	delete(subs, s)
+	if len(subs) == 0 {
+		delete(b.topics, topic)
+	}
+}
+
+// Publish sends event to all streams of topic and returns the number of them,
+// streams which can not keep up (the queue is full) are closed,
+// clients can reconnect and resume by LastEventID.
+func (b *Broker) Publish(topic string, e Event) (int, error) {
+	data, err := e.encode()
+	if err != nil {
+		return 0, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	n := 0
+	for s := range b.topics[topic] {
+		if s.send(data, false) != nil {
+			s.Close()
+			continue
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// Subscribers returns the number of streams subscribed to topic
+func (b *Broker) Subscribers(topic string) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.topics[topic])
+}
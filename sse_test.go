@@ -0,0 +1,129 @@
+package gas
+
+import (
+	"bufio"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readEvent reads lines of the next event, comments are skipped
+func readEvent(r *bufio.Reader) (string, error) {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && len(lines) != 0:
+			return strings.Join(lines, "\n"), nil
+		case line == "", strings.HasPrefix(line, ":"):
+		default:
+			lines = append(lines, line)
+		}
+	}
+}
+
+func TestEvent_encode(t *testing.T) {
+	as := assert.New(t)
+
+	b, err := (&Event{ID: "1\n", Event: "update", Data: "a\r\nb", Retry: 3 * time.Second}).encode()
+	as.Nil(err)
+	as.Equal("id: 1\nevent: update\nretry: 3000\ndata: a\ndata: b\n\n", string(b))
+
+	b, err = (&Event{Data: H{"name": "gas"}}).encode()
+	as.Nil(err)
+	as.Equal("data: {\"name\":\"gas\"}\n\n", string(b))
+
+	_, err = (&Event{Data: make(chan int)}).encode()
+	as.NotNil(err)
+}
+
+func TestContext_SSE(t *testing.T) {
+	as := assert.New(t)
+
+	g := New()
+	broker := NewBroker()
+
+	g.Router.Get("/events", func(ctx *Context) error {
+		s := ctx.SSE(20 * time.Millisecond)
+		as.Equal("5", s.LastEventID())
+		broker.Subscribe("news", s)
+
+		return nil
+	})
+	g.Router.Get("/count", func(ctx *Context) error {
+		s := ctx.SSE()
+		go func() {
+			for i := 1; i <= 3; i++ {
+				s.Send(Event{ID: string(rune('0' + i)), Data: i})
+			}
+			s.Close()
+			as.Equal(ErrStreamClosed, s.Send(Event{Data: "closed"}))
+		}()
+
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Run(":9012")
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	// events sent before closing are written
+	resp, err := http.Get("http://localhost:9012/count")
+	as.Nil(err)
+	as.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+	r := bufio.NewReader(resp.Body)
+	for _, e := range []string{"id: 1\ndata: 1", "id: 2\ndata: 2", "id: 3\ndata: 3"} {
+		ev, err := readEvent(r)
+		as.Nil(err)
+		as.Equal(e, ev)
+	}
+	_, err = readEvent(r)
+	as.NotNil(err)
+	resp.Body.Close()
+
+	req, _ := http.NewRequest("GET", "http://localhost:9012/events", nil)
+	req.Header.Set("Last-Event-ID", "5")
+	resp, err = http.DefaultClient.Do(req)
+	as.Nil(err)
+	r = bufio.NewReader(resp.Body)
+
+	// wait for subscribing
+	for i := 0; i < 100 && broker.Subscribers("news") == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+
+	n, err := broker.Publish("news", Event{Event: "created", Data: "hello"})
+	as.Nil(err)
+	as.Equal(1, n)
+
+	ev, err := readEvent(r)
+	as.Nil(err)
+	as.Equal("event: created\ndata: hello", ev)
+
+	// disconnected client is found by heartbeat and unsubscribed
+	resp.Body.Close()
+	for i := 0; i < 100 && broker.Subscribers("news") != 0; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	as.Equal(0, broker.Subscribers("news"))
+
+	// streams are ended on shutdown
+	resp, err = http.DefaultClient.Do(req)
+	as.Nil(err)
+	defer resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	as.NoError(g.Shutdown(ctx))
+	as.NoError(<-done)
+}
@@ -0,0 +1,196 @@
+package gas
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// store keeps values of a request, it's shared with the context.Context
+// returned by StdContext which may be used in other goroutines.
+type store struct {
+	mu     sync.RWMutex
+	values map[string]interface{}
+}
+
+func (s *store) get(key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, ok := s.values[key]
+
+	return v, ok
+}
+
+func (s *store) set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+}
+
+func (ctx *Context) getStore() *store {
+	if ctx.store == nil {
+		ctx.store = &store{values: make(map[string]interface{})}
+	}
+
+	return ctx.store
+}
+
+// Set stores value of key for this request, it's used to pass data from middlewares to handlers.
+//
+// Ex:
+//
+//	func Auth(next gas.GasHandler) gas.GasHandler {
+//		return func(c *gas.Context) error {
+//			user, err := findUser(c)
+//			if err != nil {
+//				return err
+//			}
+//
+//			c.Set("user", user)
+//			return next(c)
+//		}
+//	}
+func (ctx *Context) Set(key string, value interface{}) {
+	ctx.getStore().set(key, value)
+}
+
+// Get returns value of key, ok is false if it does not exist.
+func (ctx *Context) Get(key string) (value interface{}, ok bool) {
+	if ctx.store == nil {
+		return nil, false
+	}
+
+	return ctx.store.get(key)
+}
+
+// MustGet returns value of key, it panics if the key does not exist.
+//
+// Ex:
+//
+//	user := c.MustGet("user").(*User)
+func (ctx *Context) MustGet(key string) interface{} {
+	v, ok := ctx.Get(key)
+	if !ok {
+		panic("gas: key \"" + key + "\" does not exist")
+	}
+
+	return v
+}
+
+// GetString returns value of key as string, empty string is returned
+// if it does not exist or is not a string.
+func (ctx *Context) GetString(key string) string {
+	v, _ := ctx.Get(key)
+	s, _ := v.(string)
+
+	return s
+}
+
+// GetInt returns value of key as int, it works like GetString.
+func (ctx *Context) GetInt(key string) int {
+	v, _ := ctx.Get(key)
+	n, _ := v.(int)
+
+	return n
+}
+
+// GetInt64 returns value of key as int64, it works like GetString.
+func (ctx *Context) GetInt64(key string) int64 {
+	v, _ := ctx.Get(key)
+	n, _ := v.(int64)
+
+	return n
+}
+
+// GetFloat64 returns value of key as float64, it works like GetString.
+func (ctx *Context) GetFloat64(key string) float64 {
+	v, _ := ctx.Get(key)
+	f, _ := v.(float64)
+
+	return f
+}
+
+// GetBool returns value of key as bool, it works like GetString.
+func (ctx *Context) GetBool(key string) bool {
+	v, _ := ctx.Get(key)
+	b, _ := v.(bool)
+
+	return b
+}
+
+// GetTime returns value of key as time.Time, it works like GetString.
+func (ctx *Context) GetTime(key string) time.Time {
+	v, _ := ctx.Get(key)
+	t, _ := v.(time.Time)
+
+	return t
+}
+
+// GetDuration returns value of key as time.Duration, it works like GetString.
+func (ctx *Context) GetDuration(key string) time.Duration {
+	v, _ := ctx.Get(key)
+	d, _ := v.(time.Duration)
+
+	return d
+}
+
+// GetStringSlice returns value of key as []string, it works like GetString.
+func (ctx *Context) GetStringSlice(key string) []string {
+	v, _ := ctx.Get(key)
+	s, _ := v.([]string)
+
+	return s
+}
+
+// stdContext carries values set by Context.Set
+type stdContext struct {
+	context.Context
+	store *store
+}
+
+func (c *stdContext) Value(key interface{}) interface{} {
+	if k, ok := key.(string); ok {
+		if v, ok := c.store.get(k); ok {
+			return v
+		}
+	}
+
+	return c.Context.Value(key)
+}
+
+// StdContext returns a context.Context of this request for database and http client calls,
+// values stored by Set can be read by its Value method with string keys.
+// It is cancelled when the request finishes or RequestTimeout (read from config) is reached.
+//
+// Ex:
+//
+//	rows, err := db.QueryContext(c.StdContext(), "SELECT * FROM users")
+func (ctx *Context) StdContext() context.Context {
+	if ctx.stdCtx != nil {
+		return ctx.stdCtx
+	}
+
+	var (
+		base   context.Context
+		cancel context.CancelFunc
+	)
+	if timeout := ctx.gas.configDuration("RequestTimeout", time.Second); timeout > 0 {
+		base, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		base, cancel = context.WithCancel(context.Background())
+	}
+
+	ctx.stdCtx = &stdContext{Context: base, store: ctx.getStore()}
+	ctx.cancel = cancel
+
+	return ctx.stdCtx
+}
+
+// finish cancels the context returned by StdContext
+func (ctx *Context) finish() {
+	if ctx.cancel != nil {
+		ctx.cancel()
+	}
+}
@@ -0,0 +1,144 @@
+package gas
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestContext_SetGet(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+
+	now := time.Now()
+	setUser := func(next GasHandler) GasHandler {
+		return func(c *Context) error {
+			c.Set("user", "john")
+			c.Set("id", 5)
+			c.Set("id64", int64(6))
+			c.Set("score", 1.5)
+			c.Set("admin", true)
+			c.Set("at", now)
+			c.Set("ttl", time.Minute)
+			c.Set("roles", []string{"a", "b"})
+
+			return next(c)
+		}
+	}
+
+	g.Router.Get("/user", func(c *Context) error {
+		as.Equal("john", c.MustGet("user"))
+		as.Equal("john", c.GetString("user"))
+		as.Equal(5, c.GetInt("id"))
+		as.Equal(int64(6), c.GetInt64("id64"))
+		as.Equal(1.5, c.GetFloat64("score"))
+		as.True(c.GetBool("admin"))
+		as.Equal(now, c.GetTime("at"))
+		as.Equal(time.Minute, c.GetDuration("ttl"))
+		as.Equal([]string{"a", "b"}, c.GetStringSlice("roles"))
+
+		// wrong type
+		as.Equal("", c.GetString("id"))
+		as.Equal(0, c.GetInt("user"))
+
+		return c.STRING(http.StatusOK, "ok")
+	}, setUser)
+
+	g.Router.Get("/empty", func(c *Context) error {
+		// values of the previous request are cleared
+		_, ok := c.Get("user")
+		as.False(ok)
+		as.Equal("", c.GetString("user"))
+		as.Panics(func() {
+			c.MustGet("user")
+		})
+
+		return c.STRING(http.StatusOK, "ok")
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/user").Expect().Status(http.StatusOK)
+	e.GET("/empty").Expect().Status(http.StatusOK)
+}
+
+func TestContext_StdContext(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+
+	var stdCtx context.Context
+	g.Router.Get("/", func(c *Context) error {
+		c.Set("request_id", "abc")
+		stdCtx = c.StdContext()
+		as.Equal(stdCtx, c.StdContext())
+
+		// values set later are visible too
+		c.Set("user", "john")
+
+		as.Nil(stdCtx.Err())
+		as.Equal("abc", stdCtx.Value("request_id"))
+		as.Equal("john", stdCtx.Value("user"))
+		as.Nil(stdCtx.Value("none"))
+
+		return c.STRING(http.StatusOK, "ok")
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/").Expect().Status(http.StatusOK)
+
+	// cancelled when the request finished, values are kept for goroutines
+	as.Equal(context.Canceled, stdCtx.Err())
+	as.Equal("abc", stdCtx.Value("request_id"))
+
+	// cancelled by RequestTimeout
+	g = New("testfiles/config_server.yaml")
+	g.Router.Get("/slow", func(c *Context) error {
+		select {
+		case <-c.StdContext().Done():
+			return c.StdContext().Err()
+		case <-time.After(time.Second):
+			return c.STRING(http.StatusOK, "ok")
+		}
+	})
+
+	e = newHttpExpect(t, g.Router.Handler)
+	e.GET("/slow").Expect().Status(http.StatusInternalServerError)
+}
+
+func TestContext_StdContextPanic(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+	g.Router.SetPanicHandler(func(c *Context, rcv interface{}) error {
+		// the same Context as the handler
+		return c.STRING(http.StatusInternalServerError, c.GetString("request_id"))
+	})
+
+	cancelled := make(chan struct{})
+	g.Router.Get("/", func(c *Context) error {
+		c.Set("request_id", "abc")
+
+		stdCtx := c.StdContext()
+		go func() {
+			<-stdCtx.Done()
+			close(cancelled)
+		}()
+
+		panic("boom")
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/").Expect().Status(http.StatusInternalServerError).Body().Equal("abc")
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		as.Fail("not cancelled after panic")
+	}
+}
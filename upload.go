@@ -0,0 +1,347 @@
+package gas
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// sniffLen is the number of bytes used to detect content type
+	sniffLen = 512
+
+	// defaultMaxFieldSize is the max size of a non-file field read by StreamUpload
+	defaultMaxFieldSize = 1 << 20
+
+	// defaultMaxFieldsSize is the max total size of non-file fields read by StreamUpload
+	defaultMaxFieldsSize = 10 << 20
+
+	// defaultMaxParts is the max number of parts read by StreamUpload
+	defaultMaxParts = 1000
+)
+
+// UploadOptions limits uploaded files
+type UploadOptions struct {
+	// MaxSize is the max size of a file in bytes, 0 means no limit
+	MaxSize int64
+
+	// AllowedTypes are MIME types detected from file content by http.DetectContentType,
+	// Ex: "image/png", "image/*", empty means all types are allowed.
+	AllowedTypes []string
+
+	// MaxFieldSize is the max size of a non-file field in bytes read by StreamUpload,
+	// 0 means 1MB.
+	MaxFieldSize int64
+
+	// MaxFieldsSize is the max total size of non-file fields in bytes read by StreamUpload,
+	// 0 means 10MB.
+	MaxFieldsSize int64
+
+	// MaxTotalSize is the max total size of files in bytes saved by StreamUpload, 0 means no limit
+	MaxTotalSize int64
+
+	// MaxParts is the max number of files and fields read by StreamUpload, 0 means 1000
+	MaxParts int
+}
+
+// UploadedFile is a file saved by StreamUpload
+type UploadedFile struct {
+	// Field is the form field name
+	Field string
+
+	// Filename is the base name of the file sent by client
+	Filename string
+
+	// Path is where the file is saved
+	Path string
+
+	// ContentType is detected from file content
+	ContentType string
+
+	Size int64
+}
+
+// FormFile returns the first file of name in multipart form,
+// HTTPError with status 400 is returned if it does not exist.
+func (ctx *Context) FormFile(name string) (*multipart.FileHeader, error) {
+	fhs, err := ctx.FormFiles(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return fhs[0], nil
+}
+
+// FormFiles returns all files of name in multipart form,
+// HTTPError with status 400 is returned if there is no file.
+func (ctx *Context) FormFiles(name string) ([]*multipart.FileHeader, error) {
+	mf, err := ctx.MultipartForm()
+	if err != nil {
+		return nil, NewHTTPError(http.StatusBadRequest, "invalid multipart form").SetInternal(err)
+	}
+
+	fhs := mf.File[name]
+	if len(fhs) == 0 {
+		return nil, NewHTTPError(http.StatusBadRequest, "missing file "+name)
+	}
+
+	return fhs, nil
+}
+
+// SaveUploadedFile checks file by options and saves it to dst, directories of dst are created.
+// HTTPError with status 413 is returned if the file is too large,
+// and 415 if its type is not allowed.
+//
+// Ex:
+//
+//	fh, err := ctx.FormFile("avatar")
+//	if err != nil {
+//		return err
+//	}
+//
+//	err = ctx.SaveUploadedFile(fh, "storage/avatars/"+userID+".png", gas.UploadOptions{
+//		MaxSize:      2 << 20,
+//		AllowedTypes: []string{"image/png", "image/jpeg"},
+//	})
+func (ctx *Context) SaveUploadedFile(fh *multipart.FileHeader, dst string, opts ...UploadOptions) error {
+	var opt UploadOptions
+	if len(opts) != 0 {
+		opt = opts[0]
+	}
+
+	if opt.MaxSize > 0 && fh.Size > opt.MaxSize {
+		return NewHTTPError(http.StatusRequestEntityTooLarge, "file "+fh.Filename+" is too large")
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	if err = checkContentType(fh.Filename, head[:n], opt.AllowedTypes); err != nil {
+		return err
+	}
+
+	_, err = saveFile(dst, io.MultiReader(bytes.NewReader(head[:n]), f), 0)
+
+	return err
+}
+
+// StreamUpload reads multipart form from request body and writes files into dir
+// part by part, so large files are not kept in memory. Files are checked by options
+// while reading, and saved with random names to avoid conflicts and path traversal.
+// Files already saved are removed if any of them fails.
+// HTTPError with status 413 is returned if a file or a field is too large,
+// or limits of the whole form are exceeded.
+//
+// Enable Server.StreamRequestBody in config so that the request body is not
+// read into memory before handler is called.
+//
+// Ex:
+//
+//	files, values, err := ctx.StreamUpload("storage/uploads", gas.UploadOptions{MaxSize: 1 << 30})
+//	if err != nil {
+//		return err
+//	}
+func (ctx *Context) StreamUpload(dir string, opts ...UploadOptions) (files []*UploadedFile, values map[string][]string, err error) {
+	var opt UploadOptions
+	if len(opts) != 0 {
+		opt = opts[0]
+	}
+
+	boundary := string(ctx.Request.Header.MultipartFormBoundary())
+	if boundary == "" {
+		return nil, nil, NewHTTPError(http.StatusBadRequest, "invalid multipart form")
+	}
+
+	body := ctx.RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(ctx.PostBody())
+	}
+
+	defer func() {
+		if err != nil {
+			for _, f := range files {
+				os.Remove(f.Path)
+			}
+			files = nil
+		}
+	}()
+
+	maxParts := opt.MaxParts
+	if maxParts <= 0 {
+		maxParts = defaultMaxParts
+	}
+	fieldsLeft := opt.MaxFieldsSize
+	if fieldsLeft <= 0 {
+		fieldsLeft = defaultMaxFieldsSize
+	}
+	filesLeft := opt.MaxTotalSize
+
+	values = make(map[string][]string)
+	mr := multipart.NewReader(body, boundary)
+	for n := 0; ; n++ {
+		part, perr := mr.NextPart()
+		if perr == io.EOF {
+			return files, values, nil
+		}
+		if perr != nil {
+			return files, nil, NewHTTPError(http.StatusBadRequest, "invalid multipart form").SetInternal(perr)
+		}
+		if n == maxParts {
+			return files, nil, NewHTTPError(http.StatusRequestEntityTooLarge, "too many parts")
+		}
+
+		if part.FileName() == "" {
+			maxSize := opt.MaxFieldSize
+			if maxSize <= 0 {
+				maxSize = defaultMaxFieldSize
+			}
+			if fieldsLeft < maxSize {
+				maxSize = fieldsLeft
+			}
+
+			v, verr := readField(part, maxSize)
+			if verr != nil {
+				return files, nil, verr
+			}
+			fieldsLeft -= int64(len(v))
+			values[part.FormName()] = append(values[part.FormName()], v)
+			continue
+		}
+
+		maxSize := opt.MaxSize
+		if opt.MaxTotalSize > 0 && (maxSize <= 0 || filesLeft < maxSize) {
+			if filesLeft <= 0 {
+				return files, nil, NewHTTPError(http.StatusRequestEntityTooLarge, "files are too large")
+			}
+			maxSize = filesLeft
+		}
+
+		f, ferr := streamPart(part, dir, maxSize, opt.AllowedTypes)
+		if ferr != nil {
+			return files, nil, ferr
+		}
+		filesLeft -= f.Size
+		files = append(files, f)
+	}
+}
+
+// readField reads value of a non-file part, HTTPError with status 413
+// is returned if it's longer than maxSize.
+func readField(part *multipart.Part, maxSize int64) (string, error) {
+	b, err := ioutil.ReadAll(io.LimitReader(part, maxSize+1))
+	if err != nil {
+		return "", NewHTTPError(http.StatusBadRequest, "invalid multipart form").SetInternal(err)
+	}
+	if int64(len(b)) > maxSize {
+		return "", NewHTTPError(http.StatusRequestEntityTooLarge, "field "+part.FormName()+" is too large")
+	}
+
+	return string(b), nil
+}
+
+// streamPart checks and saves file part into dir, maxSize is the max size of the file, 0 means no limit
+func streamPart(part *multipart.Part, dir string, maxSize int64, allowed []string) (*UploadedFile, error) {
+	filename := filepath.Base(part.FileName())
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(part, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, NewHTTPError(http.StatusBadRequest, "invalid multipart form").SetInternal(err)
+	}
+	head = head[:n]
+
+	if err = checkContentType(filename, head, allowed); err != nil {
+		return nil, err
+	}
+
+	name, err := randomFileName(filepath.Ext(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, name)
+	size, err := saveFile(path, io.MultiReader(bytes.NewReader(head), part), maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadedFile{
+		Field:       part.FormName(),
+		Filename:    filename,
+		Path:        path,
+		ContentType: http.DetectContentType(head),
+		Size:        size,
+	}, nil
+}
+
+// saveFile copies r to path, the file is removed and HTTPError with status 413
+// is returned if more than maxSize bytes are read.
+func saveFile(path string, r io.Reader, maxSize int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, err
+	}
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, err
+	}
+
+	if maxSize > 0 {
+		r = io.LimitReader(r, maxSize+1)
+	}
+
+	n, err := io.Copy(out, r)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && maxSize > 0 && n > maxSize {
+		err = NewHTTPError(http.StatusRequestEntityTooLarge, "file is too large")
+	}
+	if err != nil {
+		os.Remove(path)
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// checkContentType checks type detected from head is one of allowed
+func checkContentType(filename string, head []byte, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	for _, a := range allowed {
+		if a == detected || (strings.HasSuffix(a, "/*") && strings.HasPrefix(detected, a[:len(a)-1])) {
+			return nil
+		}
+	}
+
+	return NewHTTPError(http.StatusUnsupportedMediaType, "type "+detected+" of file "+filename+" is not allowed")
+}
+
+func randomFileName(ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b) + strings.ToLower(ext), nil
+}
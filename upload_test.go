@@ -0,0 +1,191 @@
+package gas
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A")
+
+func TestContext_SaveUploadedFile(t *testing.T) {
+	as := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gas_upload")
+	as.Nil(err)
+	defer os.RemoveAll(dir)
+
+	g := New("testfiles/config_test.yaml")
+
+	opts := UploadOptions{MaxSize: 64, AllowedTypes: []string{"image/*"}}
+	g.Router.Post("/avatar", func(c *Context) error {
+		fh, err := c.FormFile("avatar")
+		if err != nil {
+			return err
+		}
+
+		if err = c.SaveUploadedFile(fh, filepath.Join(dir, "avatars", fh.Filename), opts); err != nil {
+			return err
+		}
+
+		return c.STRING(http.StatusOK, fh.Filename)
+	})
+	g.Router.Post("/photos", func(c *Context) error {
+		fhs, err := c.FormFiles("photo")
+		if err != nil {
+			return err
+		}
+
+		return c.STRING(http.StatusOK, fhs[0].Filename+","+fhs[1].Filename)
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	png := append(append([]byte{}, pngHeader...), "avatar"...)
+	e.POST("/avatar").WithMultipart().
+		WithFileBytes("avatar", "a.png", png).
+		Expect().Status(http.StatusOK).Body().Equal("a.png")
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "avatars", "a.png"))
+	as.Nil(err)
+	as.Equal(png, b)
+
+	e.POST("/avatar").WithMultipart().
+		WithFormField("name", "John").
+		Expect().Status(http.StatusBadRequest).Body().Contains("missing file avatar")
+
+	// type is sniffed from content instead of file name
+	e.POST("/avatar").WithMultipart().
+		WithFileBytes("avatar", "b.png", []byte("plain text")).
+		Expect().Status(http.StatusUnsupportedMediaType)
+
+	e.POST("/avatar").WithMultipart().
+		WithFileBytes("avatar", "c.png", append(png, bytes.Repeat([]byte("x"), 64)...)).
+		Expect().Status(http.StatusRequestEntityTooLarge)
+
+	_, err = os.Stat(filepath.Join(dir, "avatars", "b.png"))
+	as.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "avatars", "c.png"))
+	as.True(os.IsNotExist(err))
+
+	e.POST("/photos").WithMultipart().
+		WithFileBytes("photo", "1.jpg", []byte("1")).
+		WithFileBytes("photo", "2.jpg", []byte("2")).
+		Expect().Status(http.StatusOK).Body().Equal("1.jpg,2.jpg")
+
+	e.POST("/photos").WithFormField("photo", "1").
+		Expect().Status(http.StatusBadRequest)
+}
+
+func TestContext_StreamUpload(t *testing.T) {
+	as := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gas_upload")
+	as.Nil(err)
+	defer os.RemoveAll(dir)
+
+	g := New("testfiles/config_test.yaml")
+	g.Server.StreamRequestBody = true
+
+	var files []*UploadedFile
+	var values map[string][]string
+	g.Router.Post("/upload", func(c *Context) error {
+		var err error
+		files, values, err = c.StreamUpload(dir, UploadOptions{
+			MaxSize:       1 << 20,
+			MaxFieldSize:  16,
+			MaxFieldsSize: 24,
+			MaxTotalSize:  1 << 20,
+			MaxParts:      4,
+		})
+		if err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusCreated)
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Run(":9014")
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	post := func(fields map[string]string, files map[string][]byte) int {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		for k, v := range fields {
+			w.WriteField(k, v)
+		}
+		for name, b := range files {
+			fw, _ := w.CreateFormFile("file", name)
+			fw.Write(b)
+		}
+		w.Close()
+
+		resp, err := http.Post("http://localhost:9014/upload", w.FormDataContentType(), &body)
+		as.Nil(err)
+		resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	big := bytes.Repeat([]byte("gas"), 300000)
+	as.Equal(http.StatusCreated, post(map[string]string{"title": "big"}, map[string][]byte{"../../big.txt": big}))
+	as.Equal([]string{"big"}, values["title"])
+	as.Len(files, 1)
+	as.Equal("file", files[0].Field)
+	as.Equal("big.txt", files[0].Filename)
+	as.Equal(int64(len(big)), files[0].Size)
+	as.Equal("text/plain; charset=utf-8", files[0].ContentType)
+	as.Equal(dir, filepath.Dir(files[0].Path))
+
+	b, err := ioutil.ReadFile(files[0].Path)
+	as.Nil(err)
+	as.Equal(big, b)
+
+	// all files of the request are removed if any of them is too large
+	files = nil
+	as.Equal(http.StatusRequestEntityTooLarge, post(nil, map[string][]byte{
+		"a.txt": []byte("a"),
+		"b.txt": bytes.Repeat([]byte("b"), 1<<20+1),
+	}))
+	as.Nil(files)
+
+	entries, err := ioutil.ReadDir(dir)
+	as.Nil(err)
+	as.Len(entries, 1)
+
+	// fields are not truncated
+	as.Equal(http.StatusCreated, post(map[string]string{"title": strings.Repeat("t", 16)}, nil))
+	as.Equal(http.StatusRequestEntityTooLarge, post(map[string]string{"title": strings.Repeat("t", 17)}, nil))
+
+	// limits of the whole form
+	as.Equal(http.StatusRequestEntityTooLarge, post(map[string]string{
+		"a": strings.Repeat("a", 16),
+		"b": strings.Repeat("b", 16),
+	}, nil))
+	as.Equal(http.StatusRequestEntityTooLarge, post(map[string]string{"a": "a", "b": "b", "c": "c", "d": "d", "e": "e"}, nil))
+	as.Equal(http.StatusRequestEntityTooLarge, post(nil, map[string][]byte{
+		"a.txt": bytes.Repeat([]byte("a"), 600000),
+		"b.txt": bytes.Repeat([]byte("b"), 600000),
+	}))
+	as.Nil(files)
+
+	entries, err = ioutil.ReadDir(dir)
+	as.Nil(err)
+	as.Len(entries, 1)
+
+	// connections dialed by the client but never used are kept until ReadTimeout on shutdown
+	http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+
+	as.NoError(g.Stop())
+	as.NoError(<-done)
+}
@@ -0,0 +1,99 @@
+package gas
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Name names the route so that its URL can be generated by Router.URL,
+// it panics if the name is used by another route.
+//
+// Ex:
+//
+//	r.Get("/users/:id", ShowUser).Name("user.show")
+func (rt *Route) Name(name string) *Route {
+	r := rt.router
+	if other, ok := r.names[name]; ok && other != rt {
+		panic("gas: route name " + name + " is already used by " + other.method + " " + other.path)
+	}
+
+	if rt.name != "" {
+		delete(r.names, rt.name)
+	}
+	rt.name = name
+	r.names[name] = rt
+
+	return rt
+}
+
+// URL generates the path of route, params fill :param and *catchall segments in order,
+// they are formatted by fmt.Sprint and escaped.
+func (rt *Route) URL(params ...interface{}) (string, error) {
+	segs := strings.Split(rt.path, "/")
+	n := 0
+	for i, seg := range segs {
+		idx := strings.IndexAny(seg, ":*")
+		if idx < 0 {
+			continue
+		}
+
+		if n >= len(params) {
+			return "", fmt.Errorf("gas: missing parameter %s of route %s", seg[idx+1:], rt.path)
+		}
+		v := fmt.Sprint(params[n])
+		n++
+
+		if seg[idx] == ':' {
+			segs[i] = seg[:idx] + url.PathEscape(v)
+			continue
+		}
+
+		// catchall value matches the rest of path, slashes are kept
+		parts := strings.Split(strings.TrimPrefix(v, "/"), "/")
+		for j, p := range parts {
+			parts[j] = url.PathEscape(p)
+		}
+		segs[i] = seg[:idx] + strings.Join(parts, "/")
+	}
+
+	if n != len(params) {
+		return "", fmt.Errorf("gas: route %s has %d parameters, got %d", rt.path, n, len(params))
+	}
+
+	return strings.Join(segs, "/"), nil
+}
+
+// URL generates the path of the route named name, see Route.URL.
+// It's also available in templates as "url".
+//
+// Ex:
+//
+//	r.Get("/users/:id/files/*filepath", ShowFile).Name("user.file")
+//
+//	r.URL("user.file", 5, "docs/a.txt") // "/users/5/files/docs/a.txt"
+//
+//	// in templates
+//	<a href="{{ url "user.file" .ID .Path }}">
+func (r *Router) URL(name string, params ...interface{}) (string, error) {
+	rt, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("gas: route %s does not exist", name)
+	}
+
+	return rt.URL(params...)
+}
+
+// URLFor generates the path of the route named name, see Router.URL.
+//
+// Ex:
+//
+//	u, err := ctx.URLFor("user.show", user.ID)
+//	if err != nil {
+//		return err
+//	}
+//
+//	return ctx.Redirect(http.StatusSeeOther, u)
+func (ctx *Context) URLFor(name string, params ...interface{}) (string, error) {
+	return ctx.gas.Router.URL(name, params...)
+}
@@ -0,0 +1,79 @@
+package gas
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestRouter_URL(t *testing.T) {
+	as := assert.New(t)
+
+	g := New("testfiles/config_test.yaml")
+
+	rt := g.Router.Get("/users/:id", indexPage).Name("user.show")
+	g.Router.Get("/users/:id/files/*filepath", indexPage).Name("user.file")
+	g.Router.Group("/api").Post("/posts/:slug", indexPage).Name("api.post")
+
+	u, err := g.Router.URL("user.show", 5)
+	as.Nil(err)
+	as.Equal("/users/5", u)
+
+	u, err = rt.URL("a b")
+	as.Nil(err)
+	as.Equal("/users/a%20b", u)
+
+	u, err = g.Router.URL("user.file", 5, "/docs/a b.txt")
+	as.Nil(err)
+	as.Equal("/users/5/files/docs/a%20b.txt", u)
+
+	u, err = g.Router.URL("api.post", "hello")
+	as.Nil(err)
+	as.Equal("/api/posts/hello", u)
+
+	_, err = g.Router.URL("user.show")
+	as.EqualError(err, "gas: missing parameter id of route /users/:id")
+
+	_, err = g.Router.URL("user.show", 1, 2)
+	as.EqualError(err, "gas: route /users/:id has 1 parameters, got 2")
+
+	_, err = g.Router.URL("none")
+	as.EqualError(err, "gas: route none does not exist")
+
+	// renamed
+	rt.Name("users.show")
+	_, err = g.Router.URL("user.show", 1)
+	as.NotNil(err)
+	u, _ = g.Router.URL("users.show", 1)
+	as.Equal("/users/1", u)
+
+	as.Panics(func() {
+		g.Router.Get("/other/:id", indexPage).Name("users.show")
+	})
+}
+
+func TestContext_URLFor(t *testing.T) {
+	g := New("testfiles/config_test.yaml", "testfiles/config_view.yaml")
+
+	g.Router.Get("/users/:id", func(ctx *Context) error {
+		return ctx.View("users/link", H{"ID": 5, "Name": "John"}, "")
+	}).Name("user.show")
+	g.Router.Post("/users", func(ctx *Context) error {
+		u, err := ctx.URLFor("user.show", 7)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Redirect(http.StatusSeeOther, u)
+	})
+	g.Router.Get("/broken", func(ctx *Context) error {
+		_, err := ctx.URLFor("none")
+		return err
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/users/1").Expect().Status(http.StatusOK).Body().Equal(`<a href="/users/5">John</a>`)
+	e.POST("/users").Expect().Status(http.StatusSeeOther).Header("Location").Equal("http://example.com/users/7")
+	e.GET("/broken").Expect().Status(http.StatusInternalServerError)
+}
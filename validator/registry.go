@@ -0,0 +1,97 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale of built-in messages, it is used
+// if the validator has no locale or no message is found in its locale.
+const DefaultLocale = "en"
+
+// RuleFunc checks value with rule param, parent is the struct or map which value belongs to,
+// value is invalid (zero reflect.Value) if the key is absent in map.
+// Returning error stops validation, it means the rule can not be checked.
+type RuleFunc func(value, parent reflect.Value, param string) (bool, error)
+
+var (
+	registryMu sync.RWMutex
+
+	// messages maps locale to message templates of rules
+	messages = map[string]map[string]string{
+		DefaultLocale: errorMessage,
+	}
+)
+
+// RegisterRule adds rule with name or replaces the built-in one,
+// rule names are case-insensitive.
+// It should be called before validating, Ex: in init function.
+//
+// Ex:
+//
+//	validator.RegisterRule("unique_username", func(value, _ reflect.Value, _ string) (bool, error) {
+//		n, err := countUsers(value.String())
+//		return n == 0, err
+//	})
+//	validator.RegisterMessages(validator.DefaultLocale, map[string]string{
+//		"unique_username": "%s has been taken",
+//	})
+func RegisterRule(name string, fn RuleFunc) {
+	registryMu.Lock()
+	ruleFuncs[strings.ToLower(name)] = fn
+	registryMu.Unlock()
+}
+
+// RegisterMessages adds message templates of locale, existing messages of the same rules are replaced.
+// The first %s in template is the field key and the second one is the rule param if any.
+//
+// Ex:
+//
+//	validator.RegisterMessages("zh-TW", map[string]string{
+//		"required": "%s 為必填",
+//		"min":      "%s 不可小於 %s",
+//	})
+func RegisterMessages(locale string, msgs map[string]string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	m, ok := messages[locale]
+	if !ok {
+		m = make(map[string]string, len(msgs))
+		messages[locale] = m
+	}
+
+	for name, msg := range msgs {
+		m[strings.ToLower(name)] = msg
+	}
+}
+
+func getRule(name string) (RuleFunc, bool) {
+	registryMu.RLock()
+	fn, ok := ruleFuncs[name]
+	registryMu.RUnlock()
+
+	return fn, ok
+}
+
+// getMessage finds message template of rule in locale, its base language (Ex: "zh" for "zh-TW")
+// and DefaultLocale in order.
+func getMessage(locale, name string) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	locales := []string{locale}
+	if i := strings.IndexAny(locale, "-_"); i != -1 {
+		locales = append(locales, locale[:i])
+	}
+	locales = append(locales, DefaultLocale)
+
+	for _, l := range locales {
+		if msg, ok := messages[l][name]; ok {
+			return msg
+		}
+	}
+
+	return "%s is invalid"
+}
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestRegisterRule(t *testing.T) {
+	as := assert.New(t)
+
+	taken := map[string]bool{"john": true}
+	RegisterRule("Unique_Username", func(value, _ reflect.Value, _ string) (bool, error) {
+		if value.Kind() != reflect.String {
+			return false, errors.New("username must be string")
+		}
+
+		return !taken[value.String()], nil
+	})
+	RegisterMessages(DefaultLocale, map[string]string{
+		"unique_username": "%s has been taken",
+	})
+
+	v := New()
+	res, err := v.Validate(map[string]interface{}{"name": "john"}, map[string]string{"name": "required,unique_username"})
+	as.Nil(err)
+	as.False(res)
+	as.Equal([]string{"name has been taken"}, v.GetErrorMessages()["name"])
+
+	v = New()
+	res, _ = v.Validate(map[string]interface{}{"name": "mary"}, map[string]string{"name": "unique_username"})
+	as.True(res)
+
+	v = New()
+	_, err = v.Validate(map[string]interface{}{"name": 1}, map[string]string{"name": "unique_username"})
+	as.NotNil(err)
+}
+
+func TestRegisterMessages(t *testing.T) {
+	as := assert.New(t)
+
+	RegisterMessages("zh", map[string]string{
+		"required": "%s 為必填",
+		"MIN":      "%s 不可小於 %s",
+	})
+
+	data := map[string]interface{}{"name": "ab", "email": ""}
+	role := map[string]string{"name": "min=3,alpha", "email": "required,email"}
+
+	// falls back to base language and then default locale
+	v := New().SetLocale("zh-TW")
+	v.Validate(map[string]interface{}{"name": "a1", "email": ""}, role)
+	as.Equal(map[string][]string{
+		"name":  {"name 不可小於 3", "name must contain only letters"},
+		"email": {"email 為必填"},
+	}, v.GetErrorMessages())
+	as.Equal("name 不可小於 3, name must contain only letters", v.GetErrorMessageByKey("name"))
+
+	v = New()
+	v.Validate(data, role)
+	as.Equal("email is required\nname must be at least 3\n", v.GetErrorMessageString())
+}
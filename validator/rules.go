@@ -0,0 +1,373 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// paramSeparator separates multiple values in a rule param, Ex: "in=a|b|c", "range=1|10"
+const paramSeparator = "|"
+
+var (
+	ruleFuncs = map[string]RuleFunc{
+		"notempty":      notEmptyRule,
+		"required":      requiredRule,
+		"min":           minRule,
+		"max":           maxRule,
+		"range":         rangeRule,
+		"email":         stringRule(isEmail),
+		"url":           stringRule(isURL),
+		"ip":            stringRule(isIP),
+		"uuid":          stringRule(uuidRegexp.MatchString),
+		"regex":         regexRule,
+		"in":            inRule,
+		"notin":         notInRule,
+		"alpha":         stringRule(isAlpha),
+		"alphanumeric":  stringRule(isAlphaNumeric),
+		"numeric":       stringRule(numericRegexp.MatchString),
+		"date":          dateRule,
+		"equalfield":    equalFieldRule,
+		"notequalfield": notEqualFieldRule,
+	}
+
+	numericRegexp = regexp.MustCompile(`^[-+]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+	uuidRegexp    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+	regexCache sync.Map
+)
+
+func notEmptyRule(value, _ reflect.Value, _ string) (bool, error) {
+	return value.IsValid() && value.Kind() == reflect.String && NotEmpty(value.String()), nil
+}
+
+func requiredRule(value, _ reflect.Value, _ string) (bool, error) {
+	return Required(value), nil
+}
+
+// indirect returns the value pointed to, ok is false if value is absent or nil.
+func indirect(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return value, false
+		}
+		value = value.Elem()
+	}
+
+	return value, value.IsValid()
+}
+
+// size returns number value of numbers, or length of strings, slices and maps.
+func size(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true
+	}
+
+	return 0, false
+}
+
+func parseFloatParam(name, param string) (float64, error) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return 0, errors.New("validator: invalid param of rule " + name + ": " + param)
+	}
+
+	return n, nil
+}
+
+func compareSize(name string, value reflect.Value, param string, ok func(s, n float64) bool) (bool, error) {
+	n, err := parseFloatParam(name, param)
+	if err != nil {
+		return false, err
+	}
+
+	value, valid := indirect(value)
+	if !valid {
+		return false, nil
+	}
+
+	s, valid := size(value)
+	if !valid {
+		return false, errors.New("validator: rule " + name + " not support type " + value.Type().String())
+	}
+
+	return ok(s, n), nil
+}
+
+// minRule checks number is not less than param, or length is not less than param
+// for strings, slices and maps.
+func minRule(value, _ reflect.Value, param string) (bool, error) {
+	return compareSize("min", value, param, func(s, n float64) bool { return s >= n })
+}
+
+// maxRule checks number is not greater than param, or length is not greater than param
+// for strings, slices and maps.
+func maxRule(value, _ reflect.Value, param string) (bool, error) {
+	return compareSize("max", value, param, func(s, n float64) bool { return s <= n })
+}
+
+// rangeRule checks both min and max, Ex: "range=1|10"
+func rangeRule(value, _ reflect.Value, param string) (bool, error) {
+	p := strings.SplitN(param, paramSeparator, 2)
+	if len(p) != 2 {
+		return false, errors.New("validator: invalid param of rule range: " + param)
+	}
+
+	min, err := parseFloatParam("range", p[0])
+	if err != nil {
+		return false, err
+	}
+
+	return compareSize("range", value, p[1], func(s, max float64) bool { return s >= min && s <= max })
+}
+
+// toString formats strings and numbers to string
+func toString(value reflect.Value) (string, bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()), true
+	}
+
+	return "", false
+}
+
+// eachString calls check with value formatted to string,
+// every element is checked if value is a slice.
+func eachString(value reflect.Value, check func(string) (bool, error)) (bool, error) {
+	value, valid := indirect(value)
+	if !valid {
+		return false, nil
+	}
+
+	if (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && value.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < value.Len(); i++ {
+			if res, err := eachString(value.Index(i), check); !res || err != nil {
+				return res, err
+			}
+		}
+
+		return true, nil
+	}
+
+	s, ok := toString(value)
+	if !ok {
+		if value.Kind() == reflect.Slice {
+			// []byte
+			s = string(value.Bytes())
+		} else {
+			return false, errors.New("validator: type " + value.Type().String() + " can not be validated as string")
+		}
+	}
+
+	return check(s)
+}
+
+// stringRule makes rule which checks value formatted to string by fn
+func stringRule(fn func(string) bool) RuleFunc {
+	return func(value, _ reflect.Value, _ string) (bool, error) {
+		return eachString(value, func(s string) (bool, error) {
+			return fn(s), nil
+		})
+	}
+}
+
+func isEmail(s string) bool {
+	a, err := mail.ParseAddress(s)
+
+	return err == nil && a.Address == s && a.Name == ""
+}
+
+func isURL(s string) bool {
+	u, err := url.ParseRequestURI(s)
+
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func isIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+func isAlpha(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isAlphaNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// regexRule checks value matches the pattern, Ex: "regex=^[a-z]+$",
+// the pattern can not contain comma since it separates rules.
+func regexRule(value, _ reflect.Value, param string) (bool, error) {
+	re, ok := regexCache.Load(param)
+	if !ok {
+		r, err := regexp.Compile(param)
+		if err != nil {
+			return false, errors.New("validator: invalid param of rule regex: " + err.Error())
+		}
+		re, _ = regexCache.LoadOrStore(param, r)
+	}
+
+	return eachString(value, func(s string) (bool, error) {
+		return re.(*regexp.Regexp).MatchString(s), nil
+	})
+}
+
+// inRule checks value is one of param, Ex: "in=admin|user"
+func inRule(value, _ reflect.Value, param string) (bool, error) {
+	list := strings.Split(param, paramSeparator)
+
+	return eachString(value, func(s string) (bool, error) {
+		for _, l := range list {
+			if s == l {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+}
+
+// notInRule checks value is not any of param, Ex: "notin=root|admin"
+func notInRule(value, _ reflect.Value, param string) (bool, error) {
+	list := strings.Split(param, paramSeparator)
+
+	return eachString(value, func(s string) (bool, error) {
+		for _, l := range list {
+			if s == l {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+}
+
+// dateRule checks value is a date of layout, Ex: "date=2006-01-02 15:04",
+// the layout is "2006-01-02" by default.
+func dateRule(value, _ reflect.Value, param string) (bool, error) {
+	if param == "" {
+		param = "2006-01-02"
+	}
+
+	return eachString(value, func(s string) (bool, error) {
+		_, err := time.Parse(param, s)
+		return err == nil, nil
+	})
+}
+
+// fieldOf finds field of struct or value of map by name
+func fieldOf(parent reflect.Value, name string) (reflect.Value, error) {
+	parent, ok := indirect(parent)
+	if ok {
+		switch parent.Kind() {
+		case reflect.Struct:
+			if f := parent.FieldByName(name); f.IsValid() {
+				return f, nil
+			}
+		case reflect.Map:
+			if parent.Type().Key().Kind() == reflect.String {
+				return parent.MapIndex(reflect.ValueOf(name).Convert(parent.Type().Key())), nil
+			}
+		}
+	}
+
+	return reflect.Value{}, errors.New("validator: field " + name + " not found")
+}
+
+func equalField(value, parent reflect.Value, name string) (bool, error) {
+	other, err := fieldOf(parent, name)
+	if err != nil {
+		return false, err
+	}
+
+	value, ok := indirect(value)
+	other, otherOK := indirect(other)
+	if !ok || !otherOK {
+		return ok == otherOK, nil
+	}
+
+	if !value.CanInterface() || !other.CanInterface() {
+		return false, errors.New("validator: field " + name + " can not be compared")
+	}
+
+	return reflect.DeepEqual(value.Interface(), other.Interface()), nil
+}
+
+// equalFieldRule checks value equals another field, Ex: `validate:"equalfield=Password"`
+func equalFieldRule(value, parent reflect.Value, param string) (bool, error) {
+	return equalField(value, parent, param)
+}
+
+// notEqualFieldRule checks value does not equal another field, Ex: `validate:"notequalfield=OldPassword"`
+func notEqualFieldRule(value, parent reflect.Value, param string) (bool, error) {
+	res, err := equalField(value, parent, param)
+
+	return !res, err
+}
+
+// messageParam formats rule param to be shown in message
+func messageParam(r rule) string {
+	switch r.name {
+	case "range":
+		return strings.Replace(r.param, paramSeparator, " and ", 1)
+	case "in", "notin":
+		return strings.Replace(r.param, paramSeparator, ", ", -1)
+	}
+
+	return r.param
+}
+
+// formatMessage fills template with key and param, param is only filled
+// if template has the second verb.
+func formatMessage(template, key string, r rule) string {
+	if strings.Count(template, "%s") > 1 {
+		return fmt.Sprintf(template, key, messageParam(r))
+	}
+
+	return fmt.Sprintf(template, key)
+}
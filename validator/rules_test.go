@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testRuleCase struct {
+	rules string
+	value interface{}
+	valid bool
+}
+
+func TestValidator_Rules(t *testing.T) {
+	as := assert.New(t)
+
+	cases := []testRuleCase{
+		{"required", "", false},
+		{"required", 0, false},
+		{"required", []string{}, false},
+		{"required", 1, true},
+		{"min=3", "ab", false},
+		{"min=3", "中文字", true},
+		{"min=3", 2, false},
+		{"min=3", 3.5, true},
+		{"min=2", []int{1}, false},
+		{"max=3", "abcd", false},
+		{"max=3", uint(3), true},
+		{"max=1", map[string]int{"a": 1}, true},
+		{"range=1|10", 0, false},
+		{"range=1|10", 10, true},
+		{"range=2|3", "abcd", false},
+		{"email", "john@example.com", true},
+		{"email", "John <john@example.com>", false},
+		{"email", "john", false},
+		{"email", []string{"a@example.com", "b"}, false},
+		{"url", "https://example.com/a?b=c", true},
+		{"url", "example.com", false},
+		{"ip", "127.0.0.1", true},
+		{"ip", "::1", true},
+		{"ip", "256.0.0.1", false},
+		{"uuid", "6ba7b810-9dad-11d1-80b4-00c04fd430c8", true},
+		{"uuid", "6ba7b810-9dad-11d1-80b4", false},
+		{"regex=^[a-z]+[0-9]$", "abc1", true},
+		{"regex=^[a-z]+[0-9]$", "abc", false},
+		{"in=admin|user", "user", true},
+		{"in=admin|user", "root", false},
+		{"in=1|2", 2, true},
+		{"in=a|b", []string{"a", "b"}, true},
+		{"notin=root|admin", "root", false},
+		{"notin=root|admin", "john", true},
+		{"alpha", "abcÄ", true},
+		{"alpha", "abc1", false},
+		{"alphanumeric", "abc1", true},
+		{"alphanumeric", "abc-1", false},
+		{"numeric", "-1.5", true},
+		{"numeric", 15, true},
+		{"numeric", "1e5", false},
+		{"numeric", "NaN", false},
+		{"date", "2017-01-31", true},
+		{"date", "2017-02-31", false},
+		{"date=2006-01-02 15:04", "2017-01-31 08:30", true},
+		{"Required,MIN=3", "abcd", true},
+	}
+
+	for _, c := range cases {
+		v := New()
+		res, err := v.Validate(map[string]interface{}{"f": c.value}, map[string]string{"f": c.rules})
+		as.Nil(err, c.rules)
+		as.Equal(c.valid, res, "%s %v", c.rules, c.value)
+	}
+}
+
+func TestValidator_RuleErrors(t *testing.T) {
+	as := assert.New(t)
+
+	for _, r := range []string{"min=a", "range=1", "regex=(", "min=1"} {
+		v := New()
+		_, err := v.Validate(map[string]interface{}{"f": struct{}{}}, map[string]string{"f": r})
+		as.NotNil(err, r)
+	}
+}
+
+type testRegisterForm struct {
+	Password        string `validate:"min=8"`
+	PasswordConfirm string `validate:"equalfield=Password"`
+	OldPassword     string `validate:"notequalfield=Password"`
+}
+
+func TestValidator_EqualField(t *testing.T) {
+	as := assert.New(t)
+
+	v := New()
+	res, _ := v.Validate(&testRegisterForm{"12345678", "12345678", "87654321"}, nil)
+	as.True(res)
+
+	v = New()
+	res, _ = v.Validate(&testRegisterForm{"12345678", "1234567", "12345678"}, nil)
+	as.False(res)
+	as.Equal("PasswordConfirm must be equal to Password", v.GetErrorMessageByKey("PasswordConfirm"))
+	as.Equal("OldPassword must not be equal to Password", v.GetErrorMessageByKey("OldPassword"))
+
+	v = New()
+	res, _ = v.Validate(map[string]string{"pass": "a", "confirm": "b"}, map[string]string{"confirm": "equalfield=pass"})
+	as.False(res)
+
+	v = New()
+	_, err := v.Validate(map[string]string{"confirm": "b"}, map[string]string{"confirm": "equalfield=Other"})
+	as.Nil(err)
+}
+
+func TestValidator_RuleMessages(t *testing.T) {
+	as := assert.New(t)
+
+	v := New()
+	v.Validate(map[string]interface{}{"name": "ab", "age": 200, "role": "root"}, map[string]string{
+		"name": "required,min=3",
+		"age":  "range=0|150",
+		"role": "in=admin|user",
+	})
+
+	as.Equal("name must be at least 3", v.GetErrorMessageByKey("name"))
+	as.Equal("age must be between 0 and 150", v.GetErrorMessageByKey("age"))
+	as.Equal("role must be one of admin, user", v.GetErrorMessageByKey("role"))
+}
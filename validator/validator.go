@@ -4,47 +4,141 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 var (
+	// errorMessage is the message template of rules in DefaultLocale, the first %s
+	// is the field key and the second one is the rule param if any.
+	// Rule names are case-insensitive, so keys are lower case.
 	errorMessage map[string]string = map[string]string{
-		"NotEmpty": "%s Can not be empty",
+		"notempty":      "%s Can not be empty",
+		"required":      "%s is required",
+		"min":           "%s must be at least %s",
+		"max":           "%s must be at most %s",
+		"range":         "%s must be between %s",
+		"email":         "%s must be a valid email address",
+		"url":           "%s must be a valid URL",
+		"ip":            "%s must be a valid IP address",
+		"uuid":          "%s must be a valid UUID",
+		"regex":         "%s format is invalid",
+		"in":            "%s must be one of %s",
+		"notin":         "%s must not be any of %s",
+		"alpha":         "%s must contain only letters",
+		"alphanumeric":  "%s must contain only letters and numbers",
+		"numeric":       "%s must be a number",
+		"date":          "%s must be a date in format %s",
+		"equalfield":    "%s must be equal to %s",
+		"notequalfield": "%s must not be equal to %s",
 	}
 )
 
+// rule is a parsed validation rule, Ex: "min=3" => {name: "min", param: "3"}
+type rule struct {
+	name  string
+	param string
+}
+
+// parseRules parses comma separated rules, Ex: "required,min=3,max=64"
+func parseRules(s string) []rule {
+	var rules []rule
+	for _, r := range strings.Split(s, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+
+		var param string
+		if i := strings.IndexByte(r, '='); i != -1 {
+			r, param = r[:i], r[i+1:]
+		}
+
+		rules = append(rules, rule{name: strings.ToLower(r), param: param})
+	}
+
+	return rules
+}
+
 type Validator struct {
-	errorMsg map[string]string
+	errorMsg map[string][]string
+	locale   string
 }
 
 func New() *Validator {
 	v := &Validator{}
-	v.errorMsg = make(map[string]string, 0)
+	v.errorMsg = make(map[string][]string, 0)
 
 	return v
 }
 
+// SetLocale sets locale of error messages, Ex: "zh-TW",
+// messages are registered by RegisterMessages.
+func (v *Validator) SetLocale(locale string) *Validator {
+	v.locale = locale
+
+	return v
+}
+
+// Validate validates data and returns true if there is no error,
+// the returned error is not nil only if data or rules can not be validated.
+//
+// Maps are validated by role, which maps key to comma separated rules.
+// Structs (or pointers to struct) and slices of structs are validated by `validate` tag,
+// nested structs and slices are validated recursively, role is ignored.
+// Error messages are keyed by field path, Ex: "Name", "Address.City", "Items[0].Name", "[1].Name"
+//
+// Rule names are case-insensitive, multiple values in param are separated by "|":
+//
+//	omitempty                    skip other rules if value is absent, zero, empty or nil
+//	required                     not zero value, not empty string, slice or map
+//	notempty                     not empty string
+//	min=3, max=64, range=3|64    number value, or length of string, slice and map
+//	email, url, ip, uuid         string format
+//	regex=^[a-z]+$               matches the pattern, which can not contain comma
+//	in=a|b, notin=a|b            one of or none of the values
+//	alpha, alphanumeric, numeric letters, letters and digits, or a number
+//	date, date=2006-01-02 15:04  date in layout, "2006-01-02" by default
+//	equalfield=Password          equals to another field of the same struct or map
+//	notequalfield=OldPassword    not equals to another field
+//
+// String rules check every element of slices, numbers are formatted as strings.
+//
+// Ex:
+//
+//	type Address struct {
+//		City string `validate:"required"`
+//	}
+//
+//	type User struct {
+//		Name    string   `validate:"required"`
+//		Address Address
+//		Emails  []string `validate:"required"`
+//		Note    string   `validate:"-"`
+//	}
+//
+//	v := validator.New()
+//	if ok, _ := v.Validate(u, nil); !ok {
+//		fmt.Println(v.GetErrorMessages()) // map[Address.City:[Address.City is required]]
+//	}
 func (v *Validator) Validate(data interface{}, role map[string]string) (bool, error) {
-	// d := data.(map[string]interface{})
-
-	// for key, value := range d {
-	//     switch role[key] {
-	//         case "NotEmpty":
-	//             res := NotEmpty(value)
-	//             if !res {
-	//                 return errors.New(fmt.Sprintf(errorMessage[key], key))
-	//             }
-
-	//     }
-	// }
 	var err error
 
-	rt := reflect.TypeOf(data)
+	rv := reflect.ValueOf(data)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
 
-	switch rt.Kind() {
+	switch rv.Kind() {
 	case reflect.Map:
 		err = v.validaeMap(data, role)
-	case reflect.Slice:
+	case reflect.Struct:
+		err = v.validateStruct(rv, "")
+	case reflect.Slice, reflect.Array:
+		err = v.validateSlice(rv, "")
 	default:
+		err = errors.New(fmt.Sprint(data) + " not support to validate")
 	}
 
 	return !v.HasError(), err
@@ -53,49 +147,161 @@ func (v *Validator) Validate(data interface{}, role map[string]string) (bool, er
 func (v *Validator) validaeMap(data interface{}, role map[string]string) error {
 	switch dt := data.(type) {
 	case map[string]string:
-		v.validateMapStringString(data.(map[string]string), role)
+		return v.validateMapStringString(dt, role)
 	case map[string]interface{}:
-		v.validateMapStringInterface(data.(map[string]interface{}), role)
+		return v.validateMapStringInterface(dt, role)
 	default:
 		return errors.New(fmt.Sprint(dt) + " not support to validate")
 	}
+}
+
+func (v *Validator) validateMapStringInterface(data map[string]interface{}, role map[string]string) error {
+	parent := reflect.ValueOf(data)
+	for key, rules := range role {
+		var rv reflect.Value
+		if d, ok := data[key]; ok {
+			rv = reflect.ValueOf(d)
+		}
+
+		if err := v.validateValue(key, rv, parent, rules); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (v *Validator) validateMapStringInterface(data map[string]interface{}, role map[string]string) bool {
+func (v *Validator) validateMapStringString(data map[string]string, role map[string]string) error {
+	parent := reflect.ValueOf(data)
+	for key, rules := range role {
+		var rv reflect.Value
+		if d, ok := data[key]; ok {
+			rv = reflect.ValueOf(d)
+		}
+
+		if err := v.validateValue(key, rv, parent, rules); err != nil {
+			return err
+		}
+	}
 
-	return false
+	return nil
 }
 
-func (v *Validator) validateMapStringString(data map[string]string, role map[string]string) {
-	for key, value := range role {
-		var res bool
-		d, ok := data[key]
-		if ok {
-			res = v.doValidate(d, value)
+// validateStruct validates fields of sv by `validate` tag, prefix is the path of sv.
+func (v *Validator) validateStruct(sv reflect.Value, prefix string) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		// unexported
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+
+		tag := sf.Tag.Get("validate")
+		if tag == "-" {
+			continue
+		}
+
+		key := sf.Name
+		if sf.Anonymous {
+			// embedded struct shares the path of its parent
+			key = strings.TrimSuffix(prefix, ".")
 		} else {
-			res = false
+			key = prefix + key
+		}
+
+		fv := sv.Field(i)
+		if err := v.validateValue(key, fv, sv, tag); err != nil {
+			return err
+		}
+
+		if err := v.validateNested(key, fv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateNested validates structs and slices of structs inside fv
+func (v *Validator) validateNested(key string, fv reflect.Value) error {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+
+	switch fv.Kind() {
+	case reflect.Struct:
+		prefix := key + "."
+		if key == "" {
+			prefix = ""
+		}
+
+		return v.validateStruct(fv, prefix)
+	case reflect.Slice, reflect.Array:
+		return v.validateSlice(fv, key)
+	}
+
+	return nil
+}
+
+// validateSlice validates struct elements of sv, keys are indexed like "Items[0].Name"
+func (v *Validator) validateSlice(sv reflect.Value, key string) error {
+	for i := 0; i < sv.Len(); i++ {
+		if err := v.validateNested(key+"["+strconv.Itoa(i)+"]", sv.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateValue checks rules on value, all failed rules are recorded for key,
+// except that the others are skipped if required or notempty fails,
+// or omitempty is given and value is empty.
+func (v *Validator) validateValue(key string, value, parent reflect.Value, rules string) error {
+	for _, r := range parseRules(rules) {
+		if r.name == "omitempty" {
+			if !Required(value) {
+				break
+			}
+			continue
+		}
+
+		res, err := v.doValidate(value, parent, r)
+		if err != nil {
+			return err
 		}
+
 		if !res {
-			// set error message
-			errmsg := fmt.Sprintf(errorMessage[role[key]], key)
-			if v.errorMsg == nil {
-				v.errorMsg = make(map[string]string, 0)
+			v.setError(key, r)
+			if r.name == "required" || r.name == "notempty" {
+				break
 			}
-			v.errorMsg[key] = errmsg
 		}
 	}
 
+	return nil
+}
+
+func (v *Validator) setError(key string, r rule) {
+	msg := getMessage(v.locale, r.name)
+
+	if v.errorMsg == nil {
+		v.errorMsg = make(map[string][]string, 0)
+	}
+	v.errorMsg[key] = append(v.errorMsg[key], formatMessage(msg, key, r))
 }
 
-func (v *Validator) doValidate(value interface{}, role string) (res bool) {
-	switch role {
-	case "NotEmpty":
-		res = NotEmpty(value)
+func (v *Validator) doValidate(value, parent reflect.Value, r rule) (bool, error) {
+	fn, ok := getRule(r.name)
+	if !ok {
+		return false, errors.New("validator: unknown rule " + r.name)
 	}
 
-	return
+	return fn(value, parent, r.param)
 }
 
 func (v *Validator) HasError() bool {
@@ -108,23 +314,34 @@ func (v *Validator) GetErrors() error {
 	return errors.New(s)
 }
 
-func (v *Validator) GetErrorMessages() map[string]string {
+// GetErrorMessages returns error messages of every failed field
+func (v *Validator) GetErrorMessages() map[string][]string {
 	return v.errorMsg
 }
 
+// GetErrorMessageByKey returns error messages of field k joined by ", "
 func (v *Validator) GetErrorMessageByKey(k string) string {
 	m, ok := v.errorMsg[k]
 	if ok {
-		return m
+		return strings.Join(m, ", ")
 	}
 
 	return k + " is valid(No error)"
 }
 
+// GetErrorMessageString returns all error messages sorted by key, one message per line
 func (v *Validator) GetErrorMessageString() string {
+	keys := make([]string, 0, len(v.errorMsg))
+	for k := range v.errorMsg {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	var finalmsg string
-	for _, msg := range v.errorMsg {
-		finalmsg += msg + "\n"
+	for _, k := range keys {
+		for _, msg := range v.errorMsg[k] {
+			finalmsg += msg + "\n"
+		}
 	}
 
 	return finalmsg
@@ -149,3 +366,20 @@ func NotEmpty(data interface{}) (res bool) {
 
 	return
 }
+
+// Required reports whether value is present and not zero value,
+// empty strings, slices and maps and nil pointers are not accepted.
+func Required(value reflect.Value) bool {
+	if !value.IsValid() {
+		return false
+	}
+
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return value.Len() != 0
+	case reflect.Ptr, reflect.Interface:
+		return !value.IsNil()
+	}
+
+	return !value.IsZero()
+}
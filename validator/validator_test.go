@@ -24,3 +24,128 @@ func TestValidator_Validate(t *testing.T) {
 
 	as.Equal(true, res)
 }
+
+type testAddress struct {
+	City string `validate:"required"`
+	Zip  string
+}
+
+type testItem struct {
+	Name string `validate:"required"`
+}
+
+type testBase struct {
+	ID int `validate:"required"`
+}
+
+type testUser struct {
+	testBase
+
+	Name     string `validate:"NotEmpty"`
+	Address  testAddress
+	Shipping *testAddress
+	Items    []testItem `validate:"required"`
+	Note     string     `validate:"-"`
+}
+
+func TestValidator_ValidateStruct(t *testing.T) {
+	as := assert.New(t)
+
+	u := &testUser{
+		testBase: testBase{ID: 1},
+		Name:     "John",
+		Address:  testAddress{City: "Taipei"},
+		Items:    []testItem{{Name: "book"}},
+	}
+
+	v := New()
+	res, err := v.Validate(u, nil)
+	as.Nil(err)
+	as.True(res)
+
+	u.ID = 0
+	u.Name = ""
+	u.Address.City = ""
+	u.Shipping = &testAddress{}
+	u.Items = append(u.Items, testItem{})
+
+	v = New()
+	res, err = v.Validate(u, nil)
+	as.Nil(err)
+	as.False(res)
+	as.Equal(map[string][]string{
+		"ID":            {"ID is required"},
+		"Name":          {"Name Can not be empty"},
+		"Address.City":  {"Address.City is required"},
+		"Shipping.City": {"Shipping.City is required"},
+		"Items[1].Name": {"Items[1].Name is required"},
+	}, v.GetErrorMessages())
+
+	u.Items = nil
+	v = New()
+	v.Validate(u, nil)
+	as.Equal("Items is required", v.GetErrorMessageByKey("Items"))
+}
+
+func TestValidator_ValidateSlice(t *testing.T) {
+	as := assert.New(t)
+
+	v := New()
+	res, err := v.Validate([]testItem{{Name: "a"}, {}}, nil)
+	as.Nil(err)
+	as.False(res)
+	as.Equal("[1].Name is required", v.GetErrorMessageByKey("[1].Name"))
+}
+
+func TestValidator_ValidateMap(t *testing.T) {
+	as := assert.New(t)
+
+	v := New()
+	res, err := v.Validate(map[string]interface{}{"name": "John", "age": 0}, map[string]string{
+		"name": "required",
+		"age":  "required",
+		"tags": "required",
+	})
+	as.Nil(err)
+	as.False(res)
+	as.Len(v.GetErrorMessages(), 2)
+	as.Equal("age is required", v.GetErrorMessageByKey("age"))
+	as.Equal("tags is required", v.GetErrorMessageByKey("tags"))
+
+	// unknown rule
+	v = New()
+	_, err = v.Validate(map[string]string{"name": "John"}, map[string]string{"name": "unknown"})
+	as.NotNil(err)
+}
+
+type testProfile struct {
+	Email string  `validate:"omitempty,email"`
+	Nick  *string `validate:"omitempty,min=3"`
+	Age   int     `validate:"omitempty,range=18|120"`
+}
+
+func TestValidator_OmitEmpty(t *testing.T) {
+	as := assert.New(t)
+
+	v := New()
+	res, err := v.Validate(&testProfile{}, nil)
+	as.Nil(err)
+	as.True(res)
+
+	nick := "jo"
+	v = New()
+	res, err = v.Validate(&testProfile{Email: "john", Nick: &nick, Age: 10}, nil)
+	as.Nil(err)
+	as.False(res)
+	as.Equal(map[string][]string{
+		"Email": {"Email must be a valid email address"},
+		"Nick":  {"Nick must be at least 3"},
+		"Age":   {"Age must be between 18 and 120"},
+	}, v.GetErrorMessages())
+
+	// absent keys of map
+	v = New()
+	res, err = v.Validate(map[string]string{"name": "john"}, map[string]string{"email": "omitempty,email"})
+	as.Nil(err)
+	as.True(res)
+}
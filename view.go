@@ -1 +1,353 @@
 package gas
+
+import (
+	"errors"
+	htmltemplate "html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+)
+
+// View parses templates from views directory once and caches them,
+// templates are parsed again when files are changed if Reload is true.
+//
+// Templates are named by their paths relative to Dir without extension,
+// Ex: "layouts/main", "users/index", files in Partials are parsed with
+// every page so they can be included by name.
+//
+// The ViewEngine is chosen by extension of the first template,
+// html/template is used for ".html" by default.
+//
+// Ex:
+//
+//	views/layouts/main.html
+//	<html><body>{{ template "partials/nav" . }}{{ block "content" . }}{{ end }}</body></html>
+//
+//	views/users/index.html
+//	{{ define "content" }}<ul>{{ range .Users }}<li>{{ .Name }}</li>{{ end }}</ul>{{ end }}
+//
+//	// with View.Dir "views" and View.Layout "layouts/main" in config
+//	func ListUsers(c *gas.Context) error {
+//		return c.View("users/index", gas.H{"Users": users})
+//	}
+type View struct {
+	// Dir is the views directory, template names are relative to it
+	Dir string
+
+	// Ext is appended to template names without extension
+	Ext string
+
+	// Layout is the default layout used by Context.View, empty means no layout
+	Layout string
+
+	// Partials is the directory of partials relative to Dir
+	Partials string
+
+	// Reload checks files and parses them again if changed, it's enabled in DEV mode
+	Reload bool
+
+	mu      sync.RWMutex
+	cache   map[string]*viewTemplate
+	engines map[string]ViewEngine
+	funcs   map[string]interface{}
+}
+
+// ViewEngine parses template files of an extension.
+type ViewEngine interface {
+	// Parse parses partials and then pages in order, templates are named by ViewFile.Name.
+	// Later pages can redefine blocks of the earlier ones, the returned template
+	// executes the first page. funcs are added by Engine.AddTemplateFunc.
+	Parse(pages, partials []ViewFile, funcs map[string]interface{}) (ViewTemplate, error)
+}
+
+// ViewTemplate is a parsed template of ViewEngine
+type ViewTemplate interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// ViewFile is a template file, Ex: {Name: "users/index", Path: "views/users/index.html"}
+type ViewFile struct {
+	Name string
+	Path string
+}
+
+type viewTemplate struct {
+	tmpl  ViewTemplate
+	files map[string]time.Time
+}
+
+// NewView creates view with templates directory
+func NewView(dir string) *View {
+	return &View{
+		Dir:      dir,
+		Ext:      ".html",
+		Partials: "partials",
+		cache:    make(map[string]*viewTemplate),
+		engines: map[string]ViewEngine{
+			".html": HTMLEngine{},
+		},
+		funcs: make(map[string]interface{}),
+	}
+}
+
+// configureView sets up engine's view by the View section of config,
+// registered engines and functions are kept.
+func (g *Engine) configureView() {
+	if g.View == nil {
+		g.View = NewView("")
+	}
+
+	v := g.View
+	v.Dir = g.Config.GetString("View.Dir")
+	if ext := g.Config.GetString("View.Ext"); ext != "" {
+		v.Ext = ext
+	}
+	v.Layout = g.Config.GetString("View.Layout")
+	v.Partials = g.Config.GetString("View.Partials")
+	v.Reload = g.Config.Get("Mode") == "DEV"
+	v.ClearCache()
+}
+
+// RegisterViewEngine uses ve to render templates with extension ext
+//
+// Ex:
+//
+//	g.RegisterViewEngine(".txt", gas.TextEngine{})
+func (g *Engine) RegisterViewEngine(ext string, ve ViewEngine) {
+	g.View.RegisterEngine(ext, ve)
+}
+
+// AddTemplateFunc adds function fn which can be called by name in every template
+//
+// Ex:
+//
+//	g.AddTemplateFunc("asset", func(path string) string {
+//		return "/public/" + path + "?v=" + version
+//	})
+//
+//	<script src="{{ asset "app.js" }}"></script>
+func (g *Engine) AddTemplateFunc(name string, fn interface{}) {
+	g.View.AddFunc(name, fn)
+}
+
+// RegisterEngine uses ve to render templates with extension ext
+func (v *View) RegisterEngine(ext string, ve ViewEngine) {
+	v.mu.Lock()
+	if v.engines == nil {
+		v.engines = make(map[string]ViewEngine)
+	}
+	v.engines[ext] = ve
+	v.cache = make(map[string]*viewTemplate)
+	v.mu.Unlock()
+}
+
+// AddFunc adds template function, parsed templates are cleared
+// since functions must be defined before parsing.
+func (v *View) AddFunc(name string, fn interface{}) {
+	v.mu.Lock()
+	if v.funcs == nil {
+		v.funcs = make(map[string]interface{})
+	}
+	v.funcs[name] = fn
+	v.cache = make(map[string]*viewTemplate)
+	v.mu.Unlock()
+}
+
+// Render parses templates of names in order and executes the first one,
+// the result is written to w. Later templates can redefine blocks of earlier ones,
+// so layout should be the first.
+func (v *View) Render(w io.Writer, data interface{}, names ...string) error {
+	if len(names) == 0 {
+		return errors.New("File path can not be empty")
+	}
+
+	vt, err := v.template(names)
+	if err != nil {
+		return err
+	}
+
+	return vt.tmpl.Execute(w, data)
+}
+
+// ClearCache removes all parsed templates, they will be parsed again when rendering
+func (v *View) ClearCache() {
+	v.mu.Lock()
+	v.cache = make(map[string]*viewTemplate)
+	v.mu.Unlock()
+}
+
+func (v *View) template(names []string) (*viewTemplate, error) {
+	key := strings.Join(names, "\n")
+
+	v.mu.RLock()
+	vt, ok := v.cache[key]
+	v.mu.RUnlock()
+
+	if ok && !v.Reload {
+		return vt, nil
+	}
+
+	ext := filepath.Ext(v.path(names[0]))
+
+	v.mu.RLock()
+	ve, found := v.engines[ext]
+	funcs := make(map[string]interface{}, len(v.funcs))
+	for name, fn := range v.funcs {
+		funcs[name] = fn
+	}
+	v.mu.RUnlock()
+
+	if !found {
+		return nil, errors.New("no view engine for " + ext + " templates")
+	}
+
+	pages, partials, files, err := v.files(names, ext)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && !vt.changed(files) {
+		return vt, nil
+	}
+
+	tmpl, err := ve.Parse(pages, partials, funcs)
+	if err != nil {
+		return nil, err
+	}
+	vt = &viewTemplate{tmpl: tmpl, files: files}
+
+	v.mu.Lock()
+	if v.cache == nil {
+		v.cache = make(map[string]*viewTemplate)
+	}
+	v.cache[key] = vt
+	v.mu.Unlock()
+
+	return vt, nil
+}
+
+// files returns pages of names, partials with extension ext and modification time of them
+func (v *View) files(names []string, ext string) (pages, partials []ViewFile, files map[string]time.Time, err error) {
+	files = make(map[string]time.Time)
+
+	for _, name := range names {
+		path := v.path(name)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		files[path] = info.ModTime()
+		pages = append(pages, ViewFile{Name: v.name(path), Path: path})
+	}
+
+	if v.Partials != "" {
+		err = filepath.Walk(filepath.Join(v.Dir, v.Partials), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+
+			if _, ok := files[path]; ok || info.IsDir() || filepath.Ext(path) != ext {
+				return nil
+			}
+
+			files[path] = info.ModTime()
+			partials = append(partials, ViewFile{Name: v.name(path), Path: path})
+
+			return nil
+		})
+	}
+
+	return
+}
+
+// path returns file path of template name
+func (v *View) path(name string) string {
+	if filepath.Ext(name) == "" {
+		name += v.Ext
+	}
+
+	return filepath.Join(v.Dir, filepath.FromSlash(name))
+}
+
+// name returns template name of file path, Ex: "views/users/index.html" => "users/index"
+func (v *View) name(path string) string {
+	if rel, err := filepath.Rel(v.Dir, path); err == nil && v.Dir != "" {
+		path = rel
+	}
+
+	return filepath.ToSlash(strings.TrimSuffix(path, filepath.Ext(path)))
+}
+
+func (vt *viewTemplate) changed(files map[string]time.Time) bool {
+	if len(files) != len(vt.files) {
+		return true
+	}
+
+	for path, t := range files {
+		if old, ok := vt.files[path]; !ok || !old.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HTMLEngine is ViewEngine of html/template, it executes the "gas" template
+// if it is defined, otherwise the first page.
+type HTMLEngine struct{}
+
+// Parse implements ViewEngine
+func (HTMLEngine) Parse(pages, partials []ViewFile, funcs map[string]interface{}) (ViewTemplate, error) {
+	tmpl := htmltemplate.New("").Funcs(funcs)
+
+	for _, f := range append(partials, pages...) {
+		b, err := ioutil.ReadFile(f.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = tmpl.New(f.Name).Parse(string(b)); err != nil {
+			return nil, err
+		}
+	}
+
+	if t := tmpl.Lookup("gas"); t != nil {
+		return t, nil
+	}
+
+	return tmpl.Lookup(pages[0].Name), nil
+}
+
+// TextEngine is ViewEngine of text/template, it executes the "gas" template
+// if it is defined, otherwise the first page.
+type TextEngine struct{}
+
+// Parse implements ViewEngine
+func (TextEngine) Parse(pages, partials []ViewFile, funcs map[string]interface{}) (ViewTemplate, error) {
+	tmpl := texttemplate.New("").Funcs(funcs)
+
+	for _, f := range append(partials, pages...) {
+		b, err := ioutil.ReadFile(f.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = tmpl.New(f.Name).Parse(string(b)); err != nil {
+			return nil, err
+		}
+	}
+
+	if t := tmpl.Lookup("gas"); t != nil {
+		return t, nil
+	}
+
+	return tmpl.Lookup(pages[0].Name), nil
+}
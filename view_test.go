@@ -0,0 +1,119 @@
+package gas
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContext_View(t *testing.T) {
+	// new gas
+	g := New("testfiles/config_test.yaml", "testfiles/config_view.yaml")
+
+	g.Router.Get("/", func(ctx *Context) error {
+		return ctx.View("users/index", H{"Name": "gas"})
+	})
+	g.Router.Get("/plain", func(ctx *Context) error {
+		return ctx.View("users/plain", H{"Name": "gas"}, "")
+	})
+	g.Router.Get("/missing", func(ctx *Context) error {
+		return ctx.View("users/missing", nil)
+	})
+	g.Router.Get("/broken", func(ctx *Context) error {
+		return ctx.View("users/broken", nil)
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	e.GET("/").
+		Expect().
+		Status(http.StatusOK).
+		ContentType("text/html", "utf-8").
+		Body().Equal("<html><head><title>Users</title></head><body><nav>gas</nav>\n<b>gas</b></body></html>\n")
+
+	e.GET("/plain").
+		Expect().
+		Status(http.StatusOK).
+		Body().Equal("<p>gas</p>\n")
+
+	e.GET("/missing").Expect().Status(http.StatusInternalServerError)
+	e.GET("/broken").Expect().Status(http.StatusInternalServerError)
+}
+
+func TestView_Cache(t *testing.T) {
+	as := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gas_view")
+	as.Nil(err)
+	defer os.RemoveAll(dir)
+
+	page := filepath.Join(dir, "page.html")
+	as.Nil(ioutil.WriteFile(page, []byte("v1"), 0644))
+
+	v := NewView(dir)
+	buf := &bytes.Buffer{}
+	as.Nil(v.Render(buf, nil, "page"))
+	as.Equal("v1", buf.String())
+
+	// cached
+	as.Nil(ioutil.WriteFile(page, []byte("v2"), 0644))
+	os.Chtimes(page, time.Now(), time.Now().Add(time.Second))
+	buf.Reset()
+	as.Nil(v.Render(buf, nil, "page"))
+	as.Equal("v1", buf.String())
+
+	// reloaded
+	v.Reload = true
+	buf.Reset()
+	as.Nil(v.Render(buf, nil, "page"))
+	as.Equal("v2", buf.String())
+
+	// new partial is found in reload mode
+	as.Nil(os.Mkdir(filepath.Join(dir, "partials"), 0755))
+	as.Nil(ioutil.WriteFile(filepath.Join(dir, "partials", "foot.html"), []byte("foot"), 0644))
+	as.Nil(ioutil.WriteFile(page, []byte(`v3 {{ template "partials/foot" }}`), 0644))
+	os.Chtimes(page, time.Now(), time.Now().Add(2*time.Second))
+	buf.Reset()
+	as.Nil(v.Render(buf, nil, "page"))
+	as.Equal("v3 foot", buf.String())
+
+	as.NotNil(v.Render(buf, nil))
+}
+
+func TestEngine_AddTemplateFunc(t *testing.T) {
+	as := assert.New(t)
+
+	// new gas
+	g := New("testfiles/config_test.yaml", "testfiles/config_view.yaml")
+	g.RegisterViewEngine(".txt", TextEngine{})
+
+	g.Router.Get("/html", func(ctx *Context) error {
+		return ctx.View("users/funcs", H{"Name": "<gas>"}, "")
+	})
+	g.Router.Get("/text", func(ctx *Context) error {
+		return ctx.View("mail/welcome.txt", H{"Name": "<gas>"}, "")
+	})
+
+	e := newHttpExpect(t, g.Router.Handler)
+
+	// function is not defined
+	e.GET("/html").Expect().Status(http.StatusInternalServerError)
+
+	g.AddTemplateFunc("upper", strings.ToUpper)
+
+	e.GET("/html").Expect().Status(http.StatusOK).Body().Equal("<p>&lt;GAS&gt;</p>")
+	e.GET("/text").Expect().Status(http.StatusOK).Body().Equal("Hello <GAS> & co")
+
+	// engines and functions are kept after loading config
+	g.LoadConfig("testfiles/config_view.yaml")
+	e.GET("/text").Expect().Status(http.StatusOK)
+
+	buf := &bytes.Buffer{}
+	as.NotNil(g.View.Render(buf, nil, "mail/welcome.md"))
+}
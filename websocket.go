@@ -0,0 +1,317 @@
+package gas
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/valyala/fasthttp"
+)
+
+// WebSocketPingInterval is the interval of ping messages sent to websocket clients,
+// connections are closed if no pong is read in two intervals.
+var WebSocketPingInterval = 30 * time.Second
+
+// wsWriteWait is the time allowed to write a message
+const wsWriteWait = 10 * time.Second
+
+// WebSocketHandler handles a websocket connection, the connection is closed after it returns.
+type WebSocketHandler func(*WSConn)
+
+// WSConn is a websocket connection, it's safe to write messages
+// from multiple goroutines by its Write methods.
+type WSConn struct {
+	*websocket.Conn
+
+	params    map[string]string
+	writeMu   sync.Mutex
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newDefaultUpgrader() *websocket.FastHTTPUpgrader {
+	return &websocket.FastHTTPUpgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+}
+
+// WebSocket registers handler for websocket connections on path, the request is upgraded
+// after middlewares are run, so they can reject it like any other GET route.
+// The upgrade is done by r.WebSocketUpgrader.
+//
+// Ex:
+//
+//	hub := gas.NewHub()
+//
+//	g.Router.WebSocket("/rooms/:room", func(c *gas.WSConn) {
+//		room := c.Param("room")
+//		hub.Join(room, c)
+//
+//		var msg Message
+//		for c.ReadJSON(&msg) == nil {
+//			hub.BroadcastJSON(room, msg)
+//		}
+//	}, authMiddleware)
+func (r *Router) WebSocket(path string, h WebSocketHandler, middlewares ...interface{}) *Route {
+	rt := r.set("GET", path, r.webSocketHandler(h), middlewares...)
+	rt.handlerName = funcName(h)
+
+	return rt
+}
+
+// WebSocket registers handler for websocket connections on path in the group
+func (rg *RouterGroup) WebSocket(path string, h WebSocketHandler, middlewares ...interface{}) *Route {
+	rt := rg.set("GET", path, rg.router.webSocketHandler(h), middlewares...)
+	rt.handlerName = funcName(h)
+
+	return rt
+}
+
+// upgrader returns WebSocketUpgrader of r, or the parent's if it's nil
+func (r *Router) upgrader() *websocket.FastHTTPUpgrader {
+	if r.WebSocketUpgrader == nil && r.parent != nil {
+		return r.parent.upgrader()
+	}
+
+	return r.WebSocketUpgrader
+}
+
+func (r *Router) webSocketHandler(h WebSocketHandler) GasHandler {
+	return func(c *Context) error {
+		// context is reused after upgrading, so path parameters are copied
+		params := make(map[string]string)
+		c.VisitUserValues(func(k []byte, v interface{}) {
+			if s, ok := v.(string); ok {
+				params[string(k)] = s
+			}
+		})
+		closing := r.g.closing
+
+		// handshake errors are handled by the error handler
+		var herr error
+		u := *r.upgrader()
+		u.Error = func(_ *fasthttp.RequestCtx, status int, reason error) {
+			herr = NewHTTPError(status, reason.Error())
+		}
+
+		err := u.Upgrade(c.RequestCtx, func(conn *websocket.Conn) {
+			ws := newWSConn(conn, params)
+			defer ws.Close()
+
+			ws.keepalive(WebSocketPingInterval, closing)
+			h(ws)
+		})
+		if herr != nil {
+			return herr
+		}
+
+		return err
+	}
+}
+
+func newWSConn(conn *websocket.Conn, params map[string]string) *WSConn {
+	return &WSConn{
+		Conn:   conn,
+		params: params,
+		done:   make(chan struct{}),
+	}
+}
+
+// keepalive sends pings and closes the connection if client is gone or engine shuts down
+func (c *WSConn) keepalive(interval time.Duration, closing <-chan struct{}) {
+	c.SetReadDeadline(time.Now().Add(2 * interval))
+	c.SetPongHandler(func(string) error {
+		return c.SetReadDeadline(time.Now().Add(2 * interval))
+	})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+					c.Close()
+					return
+				}
+			case <-closing:
+				c.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutdown"),
+					time.Now().Add(wsWriteWait))
+				c.Close()
+				return
+			case <-c.done:
+				return
+			}
+		}
+	}()
+}
+
+// Param returns path parameter of the upgraded request
+func (c *WSConn) Param(name string) string {
+	return c.params[name]
+}
+
+// WriteMessage writes a message, messageType is websocket.TextMessage or websocket.BinaryMessage
+func (c *WSConn) WriteMessage(messageType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.SetWriteDeadline(time.Now().Add(wsWriteWait))
+
+	return c.Conn.WriteMessage(messageType, data)
+}
+
+// WriteText writes a text message
+func (c *WSConn) WriteText(s string) error {
+	return c.WriteMessage(websocket.TextMessage, []byte(s))
+}
+
+// WriteJSON writes v encoded to json as a text message
+func (c *WSConn) WriteJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return c.WriteMessage(websocket.TextMessage, b)
+}
+
+// Close closes the connection
+func (c *WSConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.Conn.Close()
+	})
+
+	return err
+}
+
+// Done returns a channel which is closed when the connection is closed
+func (c *WSConn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Hub groups websocket connections into rooms to broadcast messages,
+// connections leave all rooms when they are closed.
+type Hub struct {
+	mu    sync.RWMutex
+	rooms map[string]map[*WSConn]struct{}
+
+	// rooms of each connection, a connection is tracked until it's closed
+	conns map[*WSConn]map[string]struct{}
+}
+
+// NewHub creates a hub
+func NewHub() *Hub {
+	return &Hub{
+		rooms: make(map[string]map[*WSConn]struct{}),
+		conns: make(map[*WSConn]map[string]struct{}),
+	}
+}
+
+// Join adds c to room, joining the same room again has no effect.
+// Closed connections are not added.
+func (h *Hub) Join(room string, c *WSConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	select {
+	case <-c.Done():
+		return
+	default:
+	}
+
+	joined, ok := h.conns[c]
+	if !ok {
+		joined = make(map[string]struct{})
+		h.conns[c] = joined
+
+		// one goroutine for each connection, however many rooms it joins
+		go func() {
+			<-c.Done()
+			h.leaveAll(c)
+		}()
+	}
+	joined[room] = struct{}{}
+
+	conns, ok := h.rooms[room]
+	if !ok {
+		conns = make(map[*WSConn]struct{})
+		h.rooms[room] = conns
+	}
+	conns[c] = struct{}{}
+}
+
+// Leave removes c from room
+func (h *Hub) Leave(room string, c *WSConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.leave(room, c)
+}
+
+func (h *Hub) leave(room string, c *WSConn) {
+	delete(h.conns[c], room)
+
+	conns, ok := h.rooms[room]
+	if !ok {
+		return
+	}
+
+	delete(conns, c)
+	if len(conns) == 0 {
+		delete(h.rooms, room)
+	}
+}
+
+// leaveAll removes closed c from all rooms it joined
+func (h *Hub) leaveAll(c *WSConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for room := range h.conns[c] {
+		h.leave(room, c)
+	}
+	delete(h.conns, c)
+}
+
+// Broadcast writes message to all connections in room, connections failed to write are closed.
+func (h *Hub) Broadcast(room string, messageType int, data []byte) {
+	h.mu.RLock()
+	conns := make([]*WSConn, 0, len(h.rooms[room]))
+	for c := range h.rooms[room] {
+		conns = append(conns, c)
+	}
+	h.mu.RUnlock()
+
+	for _, c := range conns {
+		if err := c.WriteMessage(messageType, data); err != nil {
+			c.Close()
+		}
+	}
+}
+
+// BroadcastJSON writes v encoded to json to all connections in room
+func (h *Hub) BroadcastJSON(room string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	h.Broadcast(room, websocket.TextMessage, b)
+
+	return nil
+}
+
+// Count returns the number of connections in room
+func (h *Hub) Count(room string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.rooms[room])
+}
@@ -0,0 +1,128 @@
+package gas
+
+import (
+	"context"
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRouter_WebSocket(t *testing.T) {
+	as := assert.New(t)
+
+	g := New()
+	hub := NewHub()
+
+	auth := func(next GasHandler) GasHandler {
+		return func(c *Context) error {
+			if string(c.QueryArgs().Peek("token")) != "secret" {
+				return NewHTTPError(http.StatusUnauthorized)
+			}
+
+			return next(c)
+		}
+	}
+
+	g.Router.WebSocket("/rooms/:room", func(c *WSConn) {
+		room := c.Param("room")
+		hub.Join(room, c)
+		c.WriteJSON(H{"joined": room})
+
+		msg := map[string]string{}
+		for c.ReadJSON(&msg) == nil {
+			hub.BroadcastJSON(room, msg)
+		}
+	}, auth)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Run(":9013")
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	// middlewares are run before upgrading
+	_, resp, err := websocket.DefaultDialer.Dial("ws://localhost:9013/rooms/a", nil)
+	as.Equal(websocket.ErrBadHandshake, err)
+	as.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	// not a websocket request
+	resp, err = http.Get("http://localhost:9013/rooms/a?token=secret")
+	as.Nil(err)
+	as.Equal(http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	dial := func() *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial("ws://localhost:9013/rooms/a?token=secret", nil)
+		as.Nil(err)
+
+		joined := map[string]string{}
+		as.Nil(conn.ReadJSON(&joined))
+		as.Equal("a", joined["joined"])
+
+		return conn
+	}
+
+	c1, c2 := dial(), dial()
+	as.Equal(2, hub.Count("a"))
+
+	as.Nil(c1.WriteJSON(map[string]string{"text": "hello"}))
+	for _, c := range []*websocket.Conn{c1, c2} {
+		msg := map[string]string{}
+		as.Nil(c.ReadJSON(&msg))
+		as.Equal("hello", msg["text"])
+	}
+
+	// closed connection leaves the room
+	c2.Close()
+	for i := 0; i < 100 && hub.Count("a") != 1; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	as.Equal(1, hub.Count("a"))
+
+	// connections are closed on shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	as.NoError(g.Shutdown(ctx))
+	as.NoError(<-done)
+
+	_, _, err = c1.ReadMessage()
+	as.True(websocket.IsCloseError(err, websocket.CloseGoingAway))
+}
+
+func TestHub_Join(t *testing.T) {
+	as := assert.New(t)
+
+	hub := NewHub()
+	c := &WSConn{done: make(chan struct{})}
+
+	hub.Join("a", c)
+	hub.Join("a", c)
+	hub.Join("b", c)
+	as.Equal(1, hub.Count("a"))
+	as.Equal(1, hub.Count("b"))
+
+	hub.mu.RLock()
+	as.Len(hub.conns, 1)
+	as.Len(hub.conns[c], 2)
+	hub.mu.RUnlock()
+
+	hub.Leave("a", c)
+	as.Equal(0, hub.Count("a"))
+
+	// leaves all rooms once when closed
+	close(c.done)
+	for i := 0; i < 100 && hub.Count("b") != 0; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	as.Equal(0, hub.Count("b"))
+
+	hub.mu.RLock()
+	as.Len(hub.conns, 0)
+	hub.mu.RUnlock()
+
+	// closed connections are not added
+	hub.Join("a", c)
+	as.Equal(0, hub.Count("a"))
+}